@@ -0,0 +1,224 @@
+// Package charset implements the character sets allowed in line 3 of an
+// EPC069-12 payload and a lossy transliteration into them.
+package charset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// EPC character set codes.
+const (
+	UTF8       = 1
+	ISO8859_1  = 2
+	ISO8859_2  = 3
+	ISO8859_4  = 4
+	ISO8859_5  = 5
+	ISO8859_7  = 6
+	ISO8859_10 = 7
+	ISO8859_15 = 8
+)
+
+// Replacement records one character that Transliterate could not keep.
+type Replacement struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+var names = map[int]string{
+	UTF8:       "UTF-8",
+	ISO8859_1:  "ISO-8859-1",
+	ISO8859_2:  "ISO-8859-2",
+	ISO8859_4:  "ISO-8859-4",
+	ISO8859_5:  "ISO-8859-5",
+	ISO8859_7:  "ISO-8859-7",
+	ISO8859_10: "ISO-8859-10",
+	ISO8859_15: "ISO-8859-15",
+}
+
+var decodeTables = map[int]*[96]rune{
+	ISO8859_1:  &latin1High,
+	ISO8859_2:  &latin2High,
+	ISO8859_4:  &latin4High,
+	ISO8859_5:  &cyrillicHigh,
+	ISO8859_7:  &greekHigh,
+	ISO8859_10: &latin6High,
+	ISO8859_15: &latin9High,
+}
+
+var encodeTables = func() map[int]map[rune]byte {
+	out := make(map[int]map[rune]byte, len(decodeTables))
+	for code, tbl := range decodeTables {
+		m := make(map[rune]byte, len(tbl))
+		for i, r := range tbl {
+			if r != 0 {
+				m[r] = byte(0xA0 + i)
+			}
+		}
+		out[code] = m
+	}
+	return out
+}()
+
+// Valid reports whether code is a known EPC character set.
+func Valid(code int) bool {
+	_, ok := names[code]
+	return ok
+}
+
+// Name returns the IANA-style name of an EPC character set code.
+func Name(code int) string {
+	return names[code]
+}
+
+// Parse accepts an EPC code ("1".."8") or a charset name such as
+// "utf-8" or "iso-8859-2". An empty value selects UTF-8.
+func Parse(s string) (int, bool) {
+	v := strings.ToUpper(strings.TrimSpace(s))
+	if v == "" {
+		return UTF8, true
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		return n, Valid(n)
+	}
+	v = strings.ReplaceAll(v, "_", "-")
+	if v == "UTF8" {
+		v = "UTF-8"
+	}
+	if strings.HasPrefix(v, "ISO8859") {
+		v = "ISO-8859" + strings.TrimPrefix(v, "ISO8859")
+	}
+	for code, name := range names {
+		if name == v {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// CanEncode reports whether r is representable in the character set.
+func CanEncode(r rune, code int) bool {
+	if code == UTF8 {
+		return true
+	}
+	if r < 0xA0 {
+		return true
+	}
+	_, ok := encodeTables[code][r]
+	return ok
+}
+
+// Encode converts UTF-8 text into the byte representation of the character set.
+func Encode(s string, code int) (string, error) {
+	if code == UTF8 {
+		if !utf8.ValidString(s) {
+			return "", fmt.Errorf("invalid utf-8")
+		}
+		return s, nil
+	}
+	tbl, ok := encodeTables[code]
+	if !ok {
+		return "", fmt.Errorf("unsupported charset")
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r < 0xA0 {
+			b.WriteByte(byte(r))
+			continue
+		}
+		c, ok := tbl[r]
+		if !ok {
+			return "", fmt.Errorf("character %q not representable in %s", r, names[code])
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), nil
+}
+
+// Decode converts bytes in the character set back into UTF-8 text.
+func Decode(s string, code int) (string, error) {
+	if code == UTF8 {
+		if !utf8.ValidString(s) {
+			return "", fmt.Errorf("invalid utf-8")
+		}
+		return s, nil
+	}
+	tbl, ok := decodeTables[code]
+	if !ok {
+		return "", fmt.Errorf("unsupported charset")
+	}
+	var b strings.Builder
+	b.Grow(len(s) * 2)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0xA0 {
+			b.WriteRune(rune(c))
+			continue
+		}
+		r := tbl[c-0xA0]
+		if r == 0 {
+			return "", fmt.Errorf("byte 0x%02X unassigned in %s", c, names[code])
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), nil
+}
+
+// Transliterate replaces every character that the character set cannot
+// hold with a Latin fallback (or "?" when none is known) and reports each
+// distinct replacement once, in order of first appearance.
+func Transliterate(s string, code int) (string, []Replacement) {
+	var (
+		b    strings.Builder
+		reps []Replacement
+		seen map[rune]bool
+	)
+	for _, r := range s {
+		if CanEncode(r, code) {
+			b.WriteRune(r)
+			continue
+		}
+		to := Fallback(r)
+		if !canEncodeString(to, code) {
+			to = "?"
+		}
+		b.WriteString(to)
+		if seen == nil {
+			seen = make(map[rune]bool)
+		}
+		if !seen[r] {
+			seen[r] = true
+			reps = append(reps, Replacement{From: string(r), To: to})
+		}
+	}
+	if reps == nil {
+		return s, nil
+	}
+	return b.String(), reps
+}
+
+// Fallback returns the Latin transliteration of r, or "?" when none is known.
+func Fallback(r rune) string {
+	if v, ok := latinFold[r]; ok {
+		return v
+	}
+	if v, ok := cyrillicLatin[r]; ok {
+		return v
+	}
+	if v, ok := greekLatin[r]; ok {
+		return v
+	}
+	return "?"
+}
+
+func canEncodeString(s string, code int) bool {
+	for _, r := range s {
+		if !CanEncode(r, code) {
+			return false
+		}
+	}
+	return true
+}
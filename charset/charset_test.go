@@ -0,0 +1,83 @@
+package charset
+
+import "testing"
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		code int
+		in   string
+	}{
+		{ISO8859_1, "Müller François"},
+		{ISO8859_2, "Łódź Žilina"},
+		{ISO8859_5, "Сервис Москва"},
+		{ISO8859_7, "Αθηνα"},
+		{ISO8859_15, "Prix 10€"},
+	}
+	for _, tt := range tests {
+		enc, err := Encode(tt.in, tt.code)
+		if err != nil {
+			t.Fatalf("Encode(%q, %d): %v", tt.in, tt.code, err)
+		}
+		if len(enc) != len([]rune(tt.in)) {
+			t.Fatalf("Encode(%q, %d): expected one byte per rune, got %d bytes", tt.in, tt.code, len(enc))
+		}
+		dec, err := Decode(enc, tt.code)
+		if err != nil || dec != tt.in {
+			t.Fatalf("Decode mismatch for %d: got %q err=%v want %q", tt.code, dec, err, tt.in)
+		}
+	}
+}
+
+func TestEncodeRejectsUnrepresentable(t *testing.T) {
+	if _, err := Encode("Москва", ISO8859_1); err == nil {
+		t.Fatalf("expected error for Cyrillic in ISO-8859-1")
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	out, reps := Transliterate("Жук Straße", ISO8859_2)
+	if out != "Zhuk Straße" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+	if len(reps) != 3 || reps[0] != (Replacement{From: "Ж", To: "Zh"}) {
+		t.Fatalf("unexpected replacements: %+v", reps)
+	}
+
+	out, reps = Transliterate("Αθηνα Tech", ISO8859_1)
+	if out != "Athina Tech" || len(reps) != 5 {
+		t.Fatalf("unexpected greek transliteration: %q %+v", out, reps)
+	}
+
+	out, reps = Transliterate("東京", ISO8859_1)
+	if out != "??" || len(reps) != 2 {
+		t.Fatalf("unexpected fallback: %q %+v", out, reps)
+	}
+
+	out, reps = Transliterate("Жук", UTF8)
+	if out != "Жук" || reps != nil {
+		t.Fatalf("utf-8 should not transliterate: %q %+v", out, reps)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]int{
+		"":            UTF8,
+		"1":           UTF8,
+		"utf-8":       UTF8,
+		"2":           ISO8859_1,
+		"iso-8859-2":  ISO8859_2,
+		"ISO8859_15":  ISO8859_15,
+		"iso-8859-10": ISO8859_10,
+	}
+	for in, want := range tests {
+		got, ok := Parse(in)
+		if !ok || got != want {
+			t.Fatalf("Parse(%q)=%d,%v want %d", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"0", "9", "latin-1", "iso-8859-3"} {
+		if _, ok := Parse(in); ok {
+			t.Fatalf("Parse(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+// runBICDirectory merges downloaded bank code lists into the directory file
+// read by BIC_DIRECTORY_FILE and generate --bic-directory. Sources are read
+// in flag order (Bundesbank, OeNB, Dutch list, then generic CSV); the first
+// entry for a bank code wins.
+func runBICDirectory(args []string) error {
+	fs := flag.NewFlagSet("bic-directory", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	bundesbank := fs.String("bundesbank", "", "Bundesbank bank code file (BLZ, fixed-width text)")
+	at := fs.String("at", "", "OeNB bank list (semicolon CSV with Bankleitzahl and SWIFT-Code columns)")
+	nl := fs.String("nl", "", "Dutch BIC list (semicolon CSV with Identifier and BIC columns)")
+	generic := fs.String("csv", "", "CSV with country,bank_code,bic[,name[,check_method]] rows")
+	out := fs.String("out", "bic-directory.csv", "output file path, or - for stdout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	sources := []struct {
+		path  string
+		parse func(io.Reader) ([]validate.BankEntry, error)
+	}{
+		{*bundesbank, validate.ParseBundesbankBLZ},
+		{*at, func(r io.Reader) ([]validate.BankEntry, error) {
+			return validate.ParseBankTable(r, "AT", ';', "Bankleitzahl", "SWIFT-Code", "Bankenname")
+		}},
+		{*nl, func(r io.Reader) ([]validate.BankEntry, error) {
+			return validate.ParseBankTable(r, "NL", ';', "Identifier", "BIC", "Naam betaaldienstverlener")
+		}},
+		{*generic, validate.ParseBankCSV},
+	}
+	var entries []validate.BankEntry
+	for _, src := range sources {
+		if strings.TrimSpace(src.path) == "" {
+			continue
+		}
+		got, err := readBankSource(src.path, src.parse)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, got...)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no banks read; pass at least one of --bundesbank, --at, --nl, --csv")
+	}
+	dir := validate.NewBICDirectory(entries)
+
+	if *out == "-" {
+		return validate.WriteBICDirectory(os.Stdout, dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := validate.WriteBICDirectory(f, dir); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "wrote %d banks to %s\n", dir.Len(), *out)
+	return nil
+}
+
+func readBankSource(path string, parse func(io.Reader) ([]validate.BankEntry, error)) ([]validate.BankEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
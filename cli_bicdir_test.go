@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func TestRunBICDirectory(t *testing.T) {
+	dir := t.TempDir()
+	atFile := filepath.Join(dir, "oenb.csv")
+	nlFile := filepath.Join(dir, "nl.csv")
+	csvFile := filepath.Join(dir, "extra.csv")
+	outFile := filepath.Join(dir, "bic-directory.csv")
+	files := map[string]string{
+		atFile:  "Bankleitzahl;Bankenname;SWIFT-Code\n19043;Bank Austria;BKAUATWWXXX\n",
+		nlFile:  "Identifier;BIC;Naam betaaldienstverlener\nINGB;INGBNL2A;ING Bank\n",
+		csvFile: "DE,50010517,INGDDEFFXXX,ING-DiBa\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := runBICDirectory([]string{"--at", atFile, "--nl", nlFile, "--csv", csvFile, "--out", outFile}); err != nil {
+		t.Fatalf("runBICDirectory: %v", err)
+	}
+	raw, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "country,bank_code,bic,name,check_method\n" +
+		"AT,19043,BKAUATWWXXX,Bank Austria,\n" +
+		"DE,50010517,INGDDEFFXXX,ING-DiBa,\n" +
+		"NL,INGB,INGBNL2A,ING Bank,\n"
+	if string(raw) != want {
+		t.Fatalf("unexpected directory:\n%s", raw)
+	}
+
+	if err := runBICDirectory([]string{"--out", outFile}); err == nil {
+		t.Fatalf("expected error without sources")
+	}
+
+	defer validate.SetBICDirectory(nil)
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--name", "Example GmbH",
+			"--iban", "DE12500105170648489890",
+			"--epc-version", "002",
+			"--amount", "49.90",
+			"--bic-directory", outFile,
+			"--bic-from-iban",
+			"--format", "json",
+		})
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, `INGDDEFFXXX`) || !strings.Contains(out, `"code":"bic_from_iban"`) {
+		t.Fatalf("expected derived BIC, got %q", out)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/safe-cap/sepaqx/charset"
 	"github.com/safe-cap/sepaqx/qr"
 	"github.com/safe-cap/sepaqx/validate"
 )
@@ -18,15 +19,49 @@ func runGenerate(args []string) error {
 	fs.SetOutput(os.Stderr)
 
 	name := fs.String("name", "", "receiver name")
-	scheme := fs.String("scheme", "", "QR scheme (default: epc_sct)")
+	cs := fs.String("charset", "", "EPC character set: 1..8 or name such as utf-8|iso-8859-1|iso-8859-2 (default: utf-8)")
+	sepaCharset := fs.String("sepa-charset", "", "SEPA basic Latin handling for text fields: strict|transliterate (epc_sct, bezahlcode; default: off)")
+	scheme := fs.String("scheme", "", "QR scheme: epc_sct|swiss_qr|cz_spd|sk_pay_by_square|si_upn|hu_mnb|hr_hub3|fi_barcode|bezahlcode (default: epc_sct)")
+	version := fs.String("epc-version", "", "EPC payload version: 001|002 (default: 001; 002 allows an empty BIC inside the EEA)")
 	iban := fs.String("iban", "", "receiver IBAN")
 	bic := fs.String("bic", "", "receiver BIC")
+	bicFromIBAN := fs.Bool("bic-from-iban", false, "fill an empty BIC from the bank directory (needs --bic-directory)")
+	accountCheck := fs.Bool("account-check", false, "check German account numbers against their bank's check digit method (needs --bic-directory with Bundesbank data)")
+	bicDir := fs.String("bic-directory", "", "bank directory CSV written by sepaqx bic-directory; checks the BIC against the IBAN")
 	amount := fs.String("amount", "", "amount in EUR (example: 49.90)")
+	openAmount := fs.Bool("open-amount", false, "leave the amount empty so the payer enters it (donations, open invoices)")
 	amountFormat := fs.String("amount-format", "", "amount format profile (optional): eur_dot|eur_comma|eur_grouped_space_comma|eur_grouped_dot_comma|auto_eur_lenient")
-	purpose := fs.String("purpose", "", "purpose code (defaults to GDDS)")
-	remRef := fs.String("remittance-reference", "", "structured remittance reference")
+	purpose := fs.String("purpose", "", "ISO 20022 purpose code (defaults to GDDS, si_upn: OTHR; see sepaqx purpose-codes)")
+	ibanFormat := fs.String("iban-format", "", "IBAN/BIC input profile (optional): strict|ocr_lenient (ocr_lenient needs --iban-lenient-ocr)")
+	ibanLenientOCR := fs.Bool("iban-lenient-ocr", false, "allow --iban-format ocr_lenient: clean up scanned IBAN/BIC values (labels, dashes, dots, O/0 and I/1 mix-ups) when exactly one reading is valid")
+	purposeLenient := fs.Bool("purpose-lenient", false, "accept unknown purpose codes with a warning instead of failing")
+	strictTruncation := fs.Bool("strict-truncation", false, "fail on values longer than their field instead of truncating them with a warning")
+	allowNonSEPA := fs.Bool("allow-non-sepa", false, "accept IBANs from countries outside SEPA (epc_sct, bezahlcode)")
+	remRef := fs.String("remittance-reference", "", "structured remittance reference (RF references are checked)")
+	refType := fs.String("reference-type", "", "check and normalize the reference: rf|be_ogm|no_kid|dk_fik|fi (epc_sct, bezahlcode)")
+	rfFrom := fs.String("rf-from-invoice", "", "build an ISO 11649 RF creditor reference from this invoice number")
 	remText := fs.String("remittance-text", "", "unstructured remittance text")
-	info := fs.String("information", "", "additional information")
+	info := fs.String("information", "", "additional information (swiss_qr: bill information)")
+	dueDate := fs.String("due-date", "", "due date YYYY-MM-DD (cz_spd, sk_pay_by_square, si_upn, fi_barcode)")
+	varSym := fs.String("variable-symbol", "", "variable symbol, up to 10 digits (cz_spd, sk_pay_by_square)")
+	constSym := fs.String("constant-symbol", "", "constant symbol, up to 10 digits (cz_spd) or 4 digits (sk_pay_by_square)")
+	specSym := fs.String("specific-symbol", "", "specific symbol, up to 10 digits (cz_spd, sk_pay_by_square)")
+	transferType := fs.String("transfer-type", "", "MNB transfer type: HCT|RTP (hu_mnb, default HCT)")
+	validUntil := fs.String("valid-until", "", "validity timestamp, RFC 3339 with a whole-hour offset (hu_mnb)")
+	execDate := fs.String("execution-date", "", "execution date YYYY-MM-DD, first execution for standing orders (bezahlcode)")
+	period := fs.String("period", "", "standing-order period: 1-12 months (1M) or 1-52 weeks (2W) (bezahlcode)")
+	currency := fs.String("currency", "", "currency (epc_sct: EUR; swiss_qr: CHF|EUR, default CHF; cz_spd: CZK|EUR, default CZK; sk_pay_by_square: ISO 4217 code, default EUR; si_upn: EUR; hu_mnb: HUF; hr_hub3: EUR|HRK, default EUR; fi_barcode: EUR; bezahlcode: EUR)")
+	credStreet := fs.String("creditor-street", "", "creditor street (swiss_qr, sk_pay_by_square, si_upn, hr_hub3)")
+	credBuilding := fs.String("creditor-building-number", "", "creditor building number (swiss_qr, sk_pay_by_square, si_upn, hr_hub3)")
+	credPostal := fs.String("creditor-postal-code", "", "creditor postal code (swiss_qr, sk_pay_by_square, si_upn, hr_hub3)")
+	credTown := fs.String("creditor-town", "", "creditor town (swiss_qr, sk_pay_by_square, si_upn, hr_hub3)")
+	credCountry := fs.String("creditor-country", "", "creditor country, ISO 3166 alpha-2 (swiss_qr)")
+	debtName := fs.String("debtor-name", "", "debtor name (swiss_qr, si_upn, hr_hub3; optional)")
+	debtStreet := fs.String("debtor-street", "", "debtor street (swiss_qr, si_upn, hr_hub3)")
+	debtBuilding := fs.String("debtor-building-number", "", "debtor building number (swiss_qr, si_upn, hr_hub3)")
+	debtPostal := fs.String("debtor-postal-code", "", "debtor postal code (swiss_qr, si_upn, hr_hub3)")
+	debtTown := fs.String("debtor-town", "", "debtor town (swiss_qr, si_upn, hr_hub3)")
+	debtCountry := fs.String("debtor-country", "", "debtor country, ISO 3166 alpha-2 (swiss_qr)")
 	input := fs.String("input", "", "path to JSON array with batch input records")
 	out := fs.String("out", "sepa-qr.png", "output file path (single) or output directory (batch), or - for stdout")
 	format := fs.String("format", "png", "output format: png|payload|json")
@@ -37,6 +72,18 @@ func runGenerate(args []string) error {
 	if fs.NArg() != 0 {
 		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
 	}
+	validate.SetPurposeLenient(*purposeLenient)
+	validate.SetIBANLenientOCR(*ibanLenientOCR)
+	validate.SetAllowNonSEPA(*allowNonSEPA)
+	validate.SetAccountCheck(*accountCheck)
+	validate.SetStrictTruncation(*strictTruncation)
+	if strings.TrimSpace(*bicDir) != "" {
+		dir, err := validate.LoadBICDirectory(*bicDir)
+		if err != nil {
+			return err
+		}
+		validate.SetBICDirectory(dir)
+	}
 
 	if strings.TrimSpace(*input) != "" {
 		return runGenerateBatch(*input, *out, strings.ToLower(strings.TrimSpace(*format)))
@@ -44,15 +91,48 @@ func runGenerate(args []string) error {
 
 	in := validate.Input{
 		Scheme:              *scheme,
+		Version:             *version,
+		Charset:             *cs,
+		SEPACharset:         *sepaCharset,
 		Name:                *name,
 		IBAN:                *iban,
 		BIC:                 *bic,
+		IBANFormat:          *ibanFormat,
 		Amount:              *amount,
 		AmountFormat:        *amountFormat,
+		BICFromIBAN:         *bicFromIBAN,
+		OpenAmount:          *openAmount,
 		Purpose:             *purpose,
 		RemittanceReference: *remRef,
+		ReferenceType:       *refType,
+		RFFromInvoice:       *rfFrom,
 		RemittanceText:      *remText,
 		Information:         *info,
+		Currency:            *currency,
+		DueDate:             *dueDate,
+
+		VariableSymbol: *varSym,
+		ConstantSymbol: *constSym,
+		SpecificSymbol: *specSym,
+
+		TransferType: *transferType,
+		ValidUntil:   *validUntil,
+
+		ExecutionDate: *execDate,
+		Period:        *period,
+
+		CreditorStreet:         *credStreet,
+		CreditorBuildingNumber: *credBuilding,
+		CreditorPostalCode:     *credPostal,
+		CreditorTown:           *credTown,
+		CreditorCountry:        *credCountry,
+
+		DebtorName:           *debtName,
+		DebtorStreet:         *debtStreet,
+		DebtorBuildingNumber: *debtBuilding,
+		DebtorPostalCode:     *debtPostal,
+		DebtorTown:           *debtTown,
+		DebtorCountry:        *debtCountry,
 	}
 	return runGenerateOne(in, *out, strings.ToLower(strings.TrimSpace(*format)))
 }
@@ -60,29 +140,47 @@ func runGenerate(args []string) error {
 func runGenerateOne(in validate.Input, out, format string) error {
 	cleaned, payload, err := buildPayload(in)
 	if err != nil {
+		// JSON callers get every failed field, not just the first.
+		if fieldErrs := validate.FieldErrors(err); format == "json" && fieldErrs != nil {
+			_ = json.NewEncoder(os.Stdout).Encode(map[string]any{
+				"ok":     false,
+				"error":  err.Error(),
+				"errors": fieldErrs,
+			})
+		}
 		return err
 	}
 
 	switch format {
 	case "payload":
-		_, err = fmt.Fprintln(os.Stdout, payload)
+		_, err = fmt.Fprintln(os.Stdout, payloadText(payload, cleaned.Charset))
 		return err
 	case "json":
 		resp := map[string]any{
-			"ok":           true,
-			"payload":      payload,
-			"amount_cents": cleaned.AmountCents,
+			"ok":                   true,
+			"scheme":               cleaned.Scheme,
+			"payload":              payloadText(payload, cleaned.Charset),
+			"amount_cents":         cleaned.AmountCents,
+			"open_amount":          cleaned.OpenAmount,
+			"currency":             cleaned.Currency,
+			"version":              cleaned.Version,
+			"charset":              cleaned.Charset,
+			"remittance_reference": cleaned.RemittanceReference,
+			"transliterations":     cleaned.Transliterations,
+			"warnings":             cleaned.Warnings,
+		}
+		if cleaned.ReferenceType != "" {
+			resp["reference_type"] = cleaned.ReferenceType
+		}
+		if cleaned.BezahlCodeKind != "" {
+			resp["bezahlcode_kind"] = cleaned.BezahlCodeKind
 		}
 		return json.NewEncoder(os.Stdout).Encode(resp)
 	case "png":
-		pngBytes, err := qr.MakeQR(payload, qr.DefaultPublicOptions())
+		pngBytes, err := renderPNG(cleaned.Scheme, payload)
 		if err != nil {
 			return err
 		}
-		recolored, err := qr.Recolor(pngBytes, "#000000", "transparent")
-		if err == nil {
-			pngBytes = recolored
-		}
 		return writeOutput(out, pngBytes)
 	default:
 		return errors.New("invalid --format, use: png|payload|json")
@@ -104,12 +202,18 @@ func runGenerateBatch(inputPath, out, format string) error {
 	}
 
 	type batchItem struct {
-		Index       int    `json:"index"`
-		OK          bool   `json:"ok"`
-		Payload     string `json:"payload,omitempty"`
-		AmountCents int64  `json:"amount_cents,omitempty"`
-		Error       string `json:"error,omitempty"`
-		OutFile     string `json:"out_file,omitempty"`
+		Index          int                    `json:"index"`
+		OK             bool                   `json:"ok"`
+		Payload        string                 `json:"payload,omitempty"`
+		AmountCents    int64                  `json:"amount_cents,omitempty"`
+		OpenAmount     bool                   `json:"open_amount,omitempty"`
+		Version        string                 `json:"version,omitempty"`
+		BezahlCodeKind string                 `json:"bezahlcode_kind,omitempty"`
+		Charset        int                    `json:"charset,omitempty"`
+		Warnings       []validate.Warning     `json:"warnings,omitempty"`
+		Error          string                 `json:"error,omitempty"`
+		Errors         []*validate.FieldError `json:"errors,omitempty"`
+		OutFile        string                 `json:"out_file,omitempty"`
 	}
 	items := make([]batchItem, 0, len(inputs))
 	failures := 0
@@ -126,29 +230,30 @@ func runGenerateBatch(inputPath, out, format string) error {
 	for i, in := range inputs {
 		cleaned, payload, err := buildPayload(in)
 		if err != nil {
-			items = append(items, batchItem{Index: i, OK: false, Error: err.Error()})
+			items = append(items, batchItem{Index: i, OK: false, Error: err.Error(), Errors: validate.FieldErrors(err)})
 			failures++
 			continue
 		}
 
 		item := batchItem{
-			Index:       i,
-			OK:          true,
-			Payload:     payload,
-			AmountCents: cleaned.AmountCents,
+			Index:          i,
+			OK:             true,
+			Payload:        payloadText(payload, cleaned.Charset),
+			AmountCents:    cleaned.AmountCents,
+			OpenAmount:     cleaned.OpenAmount,
+			Version:        cleaned.Version,
+			BezahlCodeKind: cleaned.BezahlCodeKind,
+			Charset:        cleaned.Charset,
+			Warnings:       cleaned.Warnings,
 		}
 
 		if format == "png" {
-			pngBytes, err := qr.MakeQR(payload, qr.DefaultPublicOptions())
+			pngBytes, err := renderPNG(cleaned.Scheme, payload)
 			if err != nil {
 				items = append(items, batchItem{Index: i, OK: false, Error: err.Error()})
 				failures++
 				continue
 			}
-			recolored, err := qr.Recolor(pngBytes, "#000000", "transparent")
-			if err == nil {
-				pngBytes = recolored
-			}
 			filePath := filepath.Join(out, fmt.Sprintf("sepa-qr-%d.png", i+1))
 			if err := writeOutput(filePath, pngBytes); err != nil {
 				items = append(items, batchItem{Index: i, OK: false, Error: err.Error()})
@@ -218,25 +323,44 @@ func buildPayload(in validate.Input) (*validate.Clean, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	if cleaned.Scheme != "epc_sct" {
-		return nil, "", errors.New("unsupported scheme")
-	}
-	payload, err := qr.BuildEPCPayload(
-		cleaned.Name,
-		cleaned.IBAN,
-		cleaned.BIC,
-		cleaned.AmountCents,
-		cleaned.Purpose,
-		cleaned.RemittanceReference,
-		cleaned.RemittanceText,
-		cleaned.Information,
-	)
+	payload, err := qr.BuildPayload(cleaned)
 	if err != nil {
 		return nil, "", err
 	}
 	return cleaned, payload, nil
 }
 
+// renderPNG renders the public black-on-transparent symbol plus any marks the
+// scheme requires.
+func renderPNG(scheme, payload string) ([]byte, error) {
+	opt := qr.SchemeOptions(scheme, qr.DefaultPublicOptions())
+	var pngBytes []byte
+	var err error
+	if qr.SchemeIsQR(scheme) {
+		pngBytes, err = qr.MakeQR(payload, opt)
+	} else {
+		pngBytes, err = qr.MakeBarcode(scheme, payload, opt)
+	}
+	if err != nil {
+		return nil, err
+	}
+	recolored, err := qr.Recolor(pngBytes, "#000000", "transparent")
+	if err == nil {
+		pngBytes = recolored
+	}
+	return qr.ApplySchemeMarks(scheme, payload, opt, 0, pngBytes)
+}
+
+// payloadText returns the payload as UTF-8 for JSON and terminal output.
+// The QR itself carries the bytes in the selected charset.
+func payloadText(payload string, cs int) string {
+	text, err := charset.Decode(payload, cs)
+	if err != nil {
+		return payload
+	}
+	return text
+}
+
 func writeOutput(path string, data []byte) error {
 	if strings.TrimSpace(path) == "-" {
 		_, err := os.Stdout.Write(data)
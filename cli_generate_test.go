@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/safe-cap/sepaqx/qr"
 )
 
 func captureStdout(t *testing.T, fn func() error) (string, error) {
@@ -219,3 +221,429 @@ func TestRunGenerate_AmountFormat(t *testing.T) {
 		t.Fatalf("payload missing normalized amount: %q", out)
 	}
 }
+
+func TestRunGenerate_Version002WithoutBIC(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--epc-version", "002",
+			"--name", "Example GmbH",
+			"--iban", "DE12500105170648489890",
+			"--amount", "49.90",
+			"--format", "json",
+		})
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+
+	var got struct {
+		OK      bool   `json:"ok"`
+		Payload string `json:"payload"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if !got.OK || got.Version != "002" {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+	if !strings.HasPrefix(got.Payload, "BCD\n002\n1\nSCT\n\n") {
+		t.Fatalf("unexpected payload header: %q", got.Payload)
+	}
+}
+
+func TestRunGenerate_RFFromInvoice(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--name", "Example GmbH",
+			"--iban", "DE12500105170648489890",
+			"--bic", "INGDDEFFXXX",
+			"--amount", "49.90",
+			"--rf-from-invoice", "539007547034",
+			"--format", "payload",
+		})
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, "\nRF18539007547034\n") {
+		t.Fatalf("payload missing generated RF reference: %q", out)
+	}
+}
+
+func TestRunGenerate_OpenAmount(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--name", "Example e.V.",
+			"--iban", "DE12500105170648489890",
+			"--bic", "INGDDEFFXXX",
+			"--purpose", "CHAR",
+			"--open-amount",
+			"--format", "payload",
+		})
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, "\nDE12500105170648489890\n\nCHAR\n") {
+		t.Fatalf("expected empty amount line: %q", out)
+	}
+
+	_, err = captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--name", "Example e.V.",
+			"--iban", "DE12500105170648489890",
+			"--bic", "INGDDEFFXXX",
+			"--amount", "0",
+			"--format", "payload",
+		})
+	})
+	if err == nil {
+		t.Fatalf("expected zero amount to be rejected")
+	}
+}
+
+func TestRunGenerate_SwissQR(t *testing.T) {
+	args := []string{
+		"--scheme", "swiss_qr",
+		"--name", "Robert Schneider AG",
+		"--iban", "CH4431999123000889012",
+		"--amount", "1949.75",
+		"--remittance-reference", "210000000003139471430009017",
+		"--creditor-street", "Rue du Lac",
+		"--creditor-building-number", "1268",
+		"--creditor-postal-code", "2501",
+		"--creditor-town", "Biel",
+		"--creditor-country", "CH",
+	}
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--format", "json"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	var got struct {
+		OK            bool   `json:"ok"`
+		Scheme        string `json:"scheme"`
+		Payload       string `json:"payload"`
+		Currency      string `json:"currency"`
+		ReferenceType string `json:"reference_type"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if !got.OK || got.Scheme != "swiss_qr" || got.Currency != "CHF" || got.ReferenceType != "QRR" {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+	if !strings.HasPrefix(got.Payload, "SPC\n0200\n1\nCH4431999123000889012\nS\nRobert Schneider AG\n") {
+		t.Fatalf("unexpected payload: %q", got.Payload)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "swiss.png")
+	if err := runGenerate(append(args, "--out", outFile)); err != nil {
+		t.Fatalf("runGenerate png: %v", err)
+	}
+	if info, err := os.Stat(outFile); err != nil || info.Size() == 0 {
+		t.Fatalf("expected png output: %v", err)
+	}
+}
+
+func TestRunGenerateBatch_CZSPD(t *testing.T) {
+	in := `[{"scheme":"cz_spd","name":"Example s.r.o.","iban":"CZ6508000000192000145399","amount":"450.00","variable_symbol":"1234567890","due_date":"2026-11-30"},
+{"scheme":"cz_spd","name":"Example s.r.o.","iban":"CZ6508000000192000145399","amount":"450.00","constant_symbol":"12A"}]`
+	inputPath := filepath.Join(t.TempDir(), "in.json")
+	if err := os.WriteFile(inputPath, []byte(in), 0o644); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{"--input", inputPath, "--format", "json"})
+	})
+	if err == nil {
+		t.Fatalf("expected error for the failed item")
+	}
+
+	var got struct {
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+		Items     []struct {
+			OK      bool   `json:"ok"`
+			Payload string `json:"payload"`
+			Error   string `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if got.Succeeded != 1 || got.Failed != 1 || len(got.Items) != 2 {
+		t.Fatalf("unexpected summary: %+v", got)
+	}
+	if want := "SPD*1.0*ACC:CZ6508000000192000145399*AM:450.00*CC:CZK*RN:Example s.r.o.*DT:20261130*X-VS:1234567890"; got.Items[0].Payload != want {
+		t.Fatalf("payload=%q, want %q", got.Items[0].Payload, want)
+	}
+	if got.Items[1].Error != "invalid constant_symbol" {
+		t.Fatalf("error=%q, want invalid constant_symbol", got.Items[1].Error)
+	}
+}
+
+func TestRunGenerate_PayBySquare(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--scheme", "sk_pay_by_square",
+			"--name", "Príklad s.r.o.",
+			"--iban", "SK3112000000198742637541",
+			"--amount", "125.50",
+			"--variable-symbol", "2026001234",
+			"--constant-symbol", "0308",
+			"--due-date", "2026-11-30",
+			"--format", "json",
+		})
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	var got struct {
+		OK      bool   `json:"ok"`
+		Scheme  string `json:"scheme"`
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if !got.OK || got.Scheme != "sk_pay_by_square" {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+	p, err := qr.ParsePayBySquare(got.Payload)
+	if err != nil {
+		t.Fatalf("parse payload: %v", err)
+	}
+	if p.AmountCents != 12550 || p.Currency != "EUR" || p.VariableSymbol != "2026001234" || p.ConstantSymbol != "0308" || p.DueDate != "2026-11-30" {
+		t.Fatalf("unexpected decoded payload: %+v", p)
+	}
+}
+
+func TestRunGenerate_SIUPN(t *testing.T) {
+	args := []string{
+		"--scheme", "si_upn",
+		"--name", "Podjetje d.o.o.",
+		"--iban", "SI56191000000123438",
+		"--amount", "125.50",
+		"--remittance-reference", "SI12 1234567890120",
+		"--remittance-text", "Plačilo računa 17",
+		"--creditor-street", "Slovenska cesta 1",
+		"--creditor-town", "Ljubljana",
+	}
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--format", "json"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	var got struct {
+		OK            bool   `json:"ok"`
+		Payload       string `json:"payload"`
+		ReferenceType string `json:"reference_type"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if !got.OK || got.ReferenceType != "SI12" || !strings.Contains(got.Payload, "\nPlačilo računa 17\n") {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "upn.png")
+	if err := runGenerate(append(args, "--out", outFile)); err != nil {
+		t.Fatalf("runGenerate png: %v", err)
+	}
+	if info, err := os.Stat(outFile); err != nil || info.Size() == 0 {
+		t.Fatalf("expected png output: %v", err)
+	}
+}
+
+func TestRunGenerate_HRHUB3(t *testing.T) {
+	args := []string{
+		"--scheme", "hr_hub3",
+		"--name", "Primatelj d.o.o.",
+		"--iban", "HR1210010051863000160",
+		"--amount", "125.50",
+		"--remittance-reference", "HR01 1234-5678",
+		"--remittance-text", "Račun 17",
+	}
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--format", "json"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	var got struct {
+		OK            bool   `json:"ok"`
+		Payload       string `json:"payload"`
+		ReferenceType string `json:"reference_type"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v\nout=%q", err, out)
+	}
+	if !got.OK || got.ReferenceType != "HR01" || !strings.HasPrefix(got.Payload, "HRVHUB30\nEUR\n000000000012550\n") {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "hub3.png")
+	if err := runGenerate(append(args, "--out", outFile)); err != nil {
+		t.Fatalf("runGenerate png: %v", err)
+	}
+	if info, err := os.Stat(outFile); err != nil || info.Size() == 0 {
+		t.Fatalf("expected png output: %v", err)
+	}
+}
+
+func TestRunGenerate_FIBarcode(t *testing.T) {
+	args := []string{
+		"--scheme", "fi_barcode",
+		"--iban", "FI79 4405 2020 0360 82",
+		"--amount", "4883.15",
+		"--remittance-reference", "RF09 8685 1625 9619 897",
+		"--due-date", "2010-06-12",
+	}
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--format", "payload"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if strings.TrimSpace(out) != "579440520200360820048831509000000868516259619897100612" {
+		t.Fatalf("unexpected payload %q", out)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "fi.png")
+	if err := runGenerate(append(args, "--out", outFile)); err != nil {
+		t.Fatalf("runGenerate png: %v", err)
+	}
+	if info, err := os.Stat(outFile); err != nil || info.Size() == 0 {
+		t.Fatalf("expected png output: %v", err)
+	}
+}
+
+func TestRunGenerate_BezahlCode(t *testing.T) {
+	args := []string{
+		"--scheme", "bezahlcode",
+		"--name", "Verein e.V.",
+		"--iban", "DE12500105170648489890",
+		"--amount", "5.00",
+		"--remittance-text", "Beitrag",
+		"--execution-date", "2026-11-01",
+		"--period", "1M",
+	}
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--format", "payload"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.HasPrefix(out, "bank://periodicsinglepaymentsepa?name=Verein%20e.V.&reason=Beitrag&") {
+		t.Fatalf("unexpected payload %q", out)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "bezahlcode.png")
+	if err := runGenerate(append(args, "--out", outFile)); err != nil {
+		t.Fatalf("runGenerate png: %v", err)
+	}
+	if info, err := os.Stat(outFile); err != nil || info.Size() == 0 {
+		t.Fatalf("expected png output: %v", err)
+	}
+}
+
+func TestRunGenerate_SEPACharset(t *testing.T) {
+	args := []string{
+		"--name", "Müller GmbH",
+		"--iban", "DE12500105170648489890",
+		"--bic", "INGDDEFFXXX",
+		"--amount", "10",
+		"--format", "json",
+	}
+	_, err := captureStdout(t, func() error { return runGenerate(append(args, "--sepa-charset", "strict")) })
+	if err == nil || err.Error() != "name contains characters outside the SEPA character set: 'ü'" {
+		t.Fatalf("expected strict error, got %v", err)
+	}
+	out, err := captureStdout(t, func() error { return runGenerate(append(args, "--sepa-charset", "transliterate")) })
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, `Mueller GmbH`) || !strings.Contains(out, `{"field":"name","from":"ü","to":"ue"}`) {
+		t.Fatalf("expected transliterated name, got %q", out)
+	}
+}
+
+func TestRunGenerate_JSONErrors(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runGenerate([]string{
+			"--iban", "DE12500105170648489891",
+			"--bic", "INGDDEFFXXX",
+			"--amount", "-1",
+			"--format", "json",
+		})
+	})
+	if err == nil || err.Error() != "name is required" {
+		t.Fatalf("expected first error, got %v", err)
+	}
+	var resp struct {
+		OK     bool   `json:"ok"`
+		Error  string `json:"error"`
+		Errors []struct {
+			Code  string `json:"code"`
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if resp.OK || resp.Error != "name is required" || len(resp.Errors) != 3 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	for i, field := range []string{"name", "iban", "amount"} {
+		if resp.Errors[i].Field != field {
+			t.Fatalf("errors[%d].field = %q, want %q", i, resp.Errors[i].Field, field)
+		}
+	}
+}
+
+func TestRunGenerate_TruncationWarning(t *testing.T) {
+	args := []string{
+		"--name", strings.Repeat("N", 75),
+		"--iban", "DE12500105170648489890",
+		"--bic", "INGDDEFFXXX",
+		"--amount", "10",
+		"--format", "json",
+	}
+	out, err := captureStdout(t, func() error { return runGenerate(args) })
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, `"code":"truncated"`) || !strings.Contains(out, `"original_length":75,"kept_length":70`) {
+		t.Fatalf("expected truncation warning, got %q", out)
+	}
+	_, err = captureStdout(t, func() error { return runGenerate(append(args, "--strict-truncation")) })
+	if err == nil || err.Error() != "name exceeds 70 characters" {
+		t.Fatalf("expected strict truncation error, got %v", err)
+	}
+}
+
+func TestRunGenerate_IBANLenientOCR(t *testing.T) {
+	args := []string{
+		"--name", "Example GmbH",
+		"--iban", "IBAN: DE12-5OO1-O517-O648-4898-9O",
+		"--bic", "INGDDEFFXXX",
+		"--amount", "10",
+		"--iban-format", "ocr_lenient",
+		"--format", "json",
+	}
+	if _, err := captureStdout(t, func() error { return runGenerate(args) }); err == nil || err.Error() != "unsupported iban_format" {
+		t.Fatalf("expected unsupported iban_format without --iban-lenient-ocr, got %v", err)
+	}
+	out, err := captureStdout(t, func() error { return runGenerate(append(args, "--iban-lenient-ocr")) })
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, "DE12500105170648489890") || !strings.Contains(out, `"code":"ocr_corrected"`) {
+		t.Fatalf("expected corrected iban, got %q", out)
+	}
+}
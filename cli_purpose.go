@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func runPurposeCodes(args []string) error {
+	fs := flag.NewFlagSet("purpose-codes", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	format := fs.String("format", "text", "output format: text|json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	codes := validate.PurposeCodes()
+	switch strings.ToLower(strings.TrimSpace(*format)) {
+	case "text":
+		for _, c := range codes {
+			if _, err := fmt.Fprintf(os.Stdout, "%s\t%s\n", c.Code, c.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		return json.NewEncoder(os.Stdout).Encode(map[string]any{
+			"ok":            true,
+			"purpose_codes": codes,
+		})
+	default:
+		return errors.New("invalid --format, use: text|json")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func TestRunPurposeCodes_Text(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runPurposeCodes(nil)
+	})
+	if err != nil {
+		t.Fatalf("runPurposeCodes: %v", err)
+	}
+	if !strings.Contains(out, "GDDS\tPurchase Sale Of Goods\n") {
+		t.Fatalf("missing GDDS line in output")
+	}
+}
+
+func TestRunPurposeCodes_JSON(t *testing.T) {
+	out, err := captureStdout(t, func() error {
+		return runPurposeCodes([]string{"--format", "json"})
+	})
+	if err != nil {
+		t.Fatalf("runPurposeCodes: %v", err)
+	}
+	var got struct {
+		OK           bool `json:"ok"`
+		PurposeCodes []struct {
+			Code string `json:"code"`
+			Name string `json:"name"`
+		} `json:"purpose_codes"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if !got.OK || len(got.PurposeCodes) == 0 {
+		t.Fatalf("unexpected output: %+v", got)
+	}
+}
+
+func TestRunGenerate_PurposeLenient(t *testing.T) {
+	args := []string{
+		"--name", "Example GmbH",
+		"--iban", "DE12500105170648489890",
+		"--bic", "INGDDEFFXXX",
+		"--amount", "49.90",
+		"--purpose", "XXXX",
+		"--format", "json",
+	}
+	if _, err := captureStdout(t, func() error { return runGenerate(args) }); err == nil || err.Error() != "unknown purpose code" {
+		t.Fatalf("expected unknown purpose code, got %v", err)
+	}
+
+	defer validate.SetPurposeLenient(false)
+	out, err := captureStdout(t, func() error {
+		return runGenerate(append(args, "--purpose-lenient"))
+	})
+	if err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if !strings.Contains(out, `"code":"unknown_purpose_code"`) {
+		t.Fatalf("expected purpose warning, got %q", out)
+	}
+}
@@ -54,6 +54,11 @@ type Config struct {
 
 	AllowQueryAPIKey  bool
 	AmountLenientOCR  bool
+	IBANLenientOCR    bool
+	PurposeLenient    bool
+	AllowNonSEPAIBAN  bool
+	IBANAccountCheck  bool
+	StrictTruncation  bool
 	TrustedProxyCIDRs []net.IPNet
 	RequireKeys       bool
 	RequireAPIKey     bool
@@ -66,6 +71,8 @@ type Config struct {
 	CacheControl      string
 
 	ErrorPNGPath string
+
+	BICDirectoryFile string
 }
 
 func Load() (*Config, error) {
@@ -147,6 +154,11 @@ func Load() (*Config, error) {
 
 	allowQueryAPIKey := parseBool(strings.TrimSpace(os.Getenv("ALLOW_QUERY_API_KEY")), false)
 	amountLenientOCR := parseBool(strings.TrimSpace(os.Getenv("AMOUNT_LENIENT_OCR")), false)
+	ibanLenientOCR := parseBool(strings.TrimSpace(os.Getenv("IBAN_LENIENT_OCR")), false)
+	purposeLenient := parseBool(strings.TrimSpace(os.Getenv("PURPOSE_LENIENT")), false)
+	allowNonSEPAIBAN := parseBool(strings.TrimSpace(os.Getenv("ALLOW_NON_SEPA_IBAN")), false)
+	ibanAccountCheck := parseBool(strings.TrimSpace(os.Getenv("IBAN_ACCOUNT_CHECK")), false)
+	strictTruncation := parseBool(strings.TrimSpace(os.Getenv("STRICT_TRUNCATION")), false)
 	requireKeys := parseBool(strings.TrimSpace(os.Getenv("REQUIRE_KEYS")), false)
 	requireAPIKey := parseBool(strings.TrimSpace(os.Getenv("REQUIRE_API_KEY")), false)
 	accessLog := parseBool(strings.TrimSpace(os.Getenv("ACCESS_LOG")), false)
@@ -158,6 +170,7 @@ func Load() (*Config, error) {
 		cacheControl = "private, max-age=60"
 	}
 	errorPNGPath := strings.TrimSpace(os.Getenv("ERROR_PNG_PATH"))
+	bicDirectoryFile := strings.TrimSpace(os.Getenv("BIC_DIRECTORY_FILE"))
 
 	trustedCIDRs, err := parseTrustedProxyCIDRs(strings.TrimSpace(os.Getenv("TRUSTED_PROXY_CIDRS")))
 	if err != nil {
@@ -190,6 +203,11 @@ func Load() (*Config, error) {
 		RateLimitBurst:    rateBurst,
 		AllowQueryAPIKey:  allowQueryAPIKey,
 		AmountLenientOCR:  amountLenientOCR,
+		IBANLenientOCR:    ibanLenientOCR,
+		PurposeLenient:    purposeLenient,
+		AllowNonSEPAIBAN:  allowNonSEPAIBAN,
+		IBANAccountCheck:  ibanAccountCheck,
+		StrictTruncation:  strictTruncation,
 		TrustedProxyCIDRs: trustedCIDRs,
 		RequireKeys:       requireKeys,
 		RequireAPIKey:     requireAPIKey,
@@ -200,6 +218,8 @@ func Load() (*Config, error) {
 		CacheControl:      cacheControl,
 
 		ErrorPNGPath: errorPNGPath,
+
+		BICDirectoryFile: bicDirectoryFile,
 	}, nil
 }
 
@@ -6,7 +6,10 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
 )
 
 type Palette struct {
@@ -33,6 +36,12 @@ type KeyConfig struct {
 	ModuleStyle  string   `json:"module_style"`
 	ModuleRadius float64  `json:"module_radius"`
 	QuietZone    int      `json:"quiet_zone"`
+	EPCVersion   string   `json:"epc_version"`
+	EPCCharset   string   `json:"epc_charset"`
+	SEPACharset  string   `json:"sepa_charset"`
+	IBANFormat   string   `json:"iban_format"`
+
+	AllowNonSEPAIBAN bool `json:"allow_non_sepa_iban"`
 }
 
 type storeFile struct {
@@ -102,6 +111,32 @@ func LoadFromFile(path string) (*Store, error) {
 			log.Printf("keys: invalid quiet_zone, disabling (name=%q, quiet_zone=%v)", k.Name, k.QuietZone)
 			k.QuietZone = 0
 		}
+		if v, ok := normalizeEPCVersion(k.EPCVersion); ok {
+			k.EPCVersion = v
+		} else {
+			log.Printf("keys: invalid epc_version, disabling (name=%q, epc_version=%q)", k.Name, k.EPCVersion)
+			k.EPCVersion = ""
+		}
+		if cs, ok := charset.Parse(k.EPCCharset); ok {
+			if strings.TrimSpace(k.EPCCharset) != "" {
+				k.EPCCharset = strconv.Itoa(cs)
+			}
+		} else {
+			log.Printf("keys: invalid epc_charset, disabling (name=%q, epc_charset=%q)", k.Name, k.EPCCharset)
+			k.EPCCharset = ""
+		}
+		if v, ok := normalizeSEPACharset(k.SEPACharset); ok {
+			k.SEPACharset = v
+		} else {
+			log.Printf("keys: invalid sepa_charset, disabling (name=%q, sepa_charset=%q)", k.Name, k.SEPACharset)
+			k.SEPACharset = ""
+		}
+		if v, ok := normalizeIBANFormat(k.IBANFormat); ok {
+			k.IBANFormat = v
+		} else {
+			log.Printf("keys: invalid iban_format, disabling (name=%q, iban_format=%q)", k.Name, k.IBANFormat)
+			k.IBANFormat = ""
+		}
 		if k.QRSize != 0 && (k.QRSize < 512 || k.QRSize > 2048) {
 			log.Printf("keys: invalid qr_size, disabling per-key override (name=%q, qr_size=%v)", k.Name, k.QRSize)
 			k.QRSize = 0
@@ -139,6 +174,37 @@ func normalizeModuleStyle(s string) string {
 	}
 }
 
+func normalizeEPCVersion(s string) (string, bool) {
+	switch strings.TrimSpace(s) {
+	case "":
+		return "", true
+	case "1", "001":
+		return "001", true
+	case "2", "002":
+		return "002", true
+	default:
+		return "", false
+	}
+}
+
+func normalizeSEPACharset(s string) (string, bool) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "", "strict", "transliterate":
+		return v, true
+	default:
+		return "", false
+	}
+}
+
+func normalizeIBANFormat(s string) (string, bool) {
+	switch v := strings.ToLower(strings.TrimSpace(s)); v {
+	case "", "strict", "ocr_lenient":
+		return v, true
+	default:
+		return "", false
+	}
+}
+
 func normalizeLogoBGShape(s string) string {
 	v := strings.TrimSpace(strings.ToLower(s))
 	switch v {
@@ -32,6 +32,18 @@ func main() {
 		}
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "bic-directory" {
+		if err := runBICDirectory(os.Args[2:]); err != nil {
+			log.Fatalf("bic-directory failed: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "purpose-codes" {
+		if err := runPurposeCodes(os.Args[2:]); err != nil {
+			log.Fatalf("purpose-codes failed: %v", err)
+		}
+		return
+	}
 
 	showVersion := flag.Bool("v", false, "print version and exit")
 	showVersionLong := flag.Bool("version", false, "print version and exit")
@@ -47,6 +59,19 @@ func main() {
 		log.Fatalf("config load failed: %v", err)
 	}
 	validate.SetAmountLenientOCR(cfg.AmountLenientOCR)
+	validate.SetIBANLenientOCR(cfg.IBANLenientOCR)
+	validate.SetPurposeLenient(cfg.PurposeLenient)
+	validate.SetAllowNonSEPA(cfg.AllowNonSEPAIBAN)
+	validate.SetAccountCheck(cfg.IBANAccountCheck)
+	validate.SetStrictTruncation(cfg.StrictTruncation)
+	if cfg.BICDirectoryFile != "" {
+		dir, err := validate.LoadBICDirectory(cfg.BICDirectoryFile)
+		if err != nil {
+			log.Fatalf("bic directory load failed: %v", err)
+		}
+		validate.SetBICDirectory(dir)
+		log.Printf("bic directory: %d banks from %s", dir.Len(), cfg.BICDirectoryFile)
+	}
 
 	config.OverrideBuildInfo(version, commit)
 
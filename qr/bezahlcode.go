@@ -0,0 +1,101 @@
+package qr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+// BezahlCodePayload holds the fields of a SEPA BezahlCode URI. Period is a
+// standing-order period such as "1M" or "2W"; when set, ExecutionDate is
+// the first execution. Dates are YYYY-MM-DD.
+type BezahlCodePayload struct {
+	Name          string
+	IBAN          string
+	BIC           string
+	AmountCents   int64
+	OpenAmount    bool
+	Reason        string
+	ExecutionDate string
+	Period        string
+}
+
+// Build returns the bank://singlepaymentsepa or
+// bank://periodicsinglepaymentsepa URI.
+func (p BezahlCodePayload) Build() (string, error) {
+	if p.Name == "" || p.IBAN == "" {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if !p.OpenAmount && p.AmountCents <= 0 {
+		return "", fmt.Errorf("invalid amount")
+	}
+	if utf8.RuneCountInString(p.Reason) > validate.BezahlCodeReasonMax {
+		return "", fmt.Errorf("reason too long")
+	}
+	execDate, err := bezahlCodeDate(p.ExecutionDate)
+	if err != nil {
+		return "", err
+	}
+
+	authority := "singlepaymentsepa"
+	// Only the free-text values need escaping; the amount keeps its
+	// decimal comma as BezahlCode readers expect.
+	params := [][2]string{
+		{"name", bezahlCodeEscape(p.Name)},
+		{"reason", bezahlCodeEscape(p.Reason)},
+		{"iban", p.IBAN},
+		{"bic", p.BIC},
+	}
+	if !p.OpenAmount {
+		params = append(params, [2]string{"amount", fmt.Sprintf("%d,%02d", p.AmountCents/100, p.AmountCents%100)})
+	}
+	if p.Period != "" {
+		if len(p.Period) < 2 {
+			return "", fmt.Errorf("invalid period")
+		}
+		authority = "periodicsinglepaymentsepa"
+		params = append(params,
+			[2]string{"periodictimeunit", p.Period[len(p.Period)-1:]},
+			[2]string{"periodictimeunitrotation", p.Period[:len(p.Period)-1]},
+			[2]string{"periodicfirstexecutiondate", execDate},
+		)
+	} else {
+		params = append(params, [2]string{"executiondate", execDate})
+	}
+
+	var b strings.Builder
+	b.WriteString("bank://")
+	b.WriteString(authority)
+	sep := "?"
+	for _, kv := range params {
+		if kv[1] == "" {
+			continue
+		}
+		b.WriteString(sep)
+		b.WriteString(kv[0])
+		b.WriteString("=")
+		b.WriteString(kv[1])
+		sep = "&"
+	}
+	return b.String(), nil
+}
+
+// bezahlCodeDate converts YYYY-MM-DD to the DDMMYYYY form BezahlCode uses.
+func bezahlCodeDate(d string) (string, error) {
+	if d == "" {
+		return "", nil
+	}
+	if len(d) != 10 {
+		return "", fmt.Errorf("invalid execution date")
+	}
+	return d[8:10] + d[5:7] + d[:4], nil
+}
+
+// bezahlCodeEscape percent-encodes a parameter value; readers expect %20
+// rather than + for spaces.
+func bezahlCodeEscape(v string) string {
+	return strings.ReplaceAll(url.QueryEscape(v), "+", "%20")
+}
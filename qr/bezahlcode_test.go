@@ -0,0 +1,57 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func TestBezahlCodePayloadBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		p    BezahlCodePayload
+		want string
+	}{
+		{
+			name: "single",
+			p:    BezahlCodePayload{Name: "Max Müller", IBAN: "DE12500105170648489890", BIC: "INGDDEFFXXX", AmountCents: 1234, Reason: "Rechnung 17 & 18", ExecutionDate: "2026-11-01"},
+			want: "bank://singlepaymentsepa?name=Max%20M%C3%BCller&reason=Rechnung%2017%20%26%2018&iban=DE12500105170648489890&bic=INGDDEFFXXX&amount=12,34&executiondate=01112026",
+		},
+		{
+			name: "periodic",
+			p:    BezahlCodePayload{Name: "Verein e.V.", IBAN: "DE12500105170648489890", AmountCents: 500, Reason: "Beitrag", ExecutionDate: "2026-11-01", Period: "1M"},
+			want: "bank://periodicsinglepaymentsepa?name=Verein%20e.V.&reason=Beitrag&iban=DE12500105170648489890&amount=5,00&periodictimeunit=M&periodictimeunitrotation=1&periodicfirstexecutiondate=01112026",
+		},
+		{
+			name: "open_amount",
+			p:    BezahlCodePayload{Name: "Spende", IBAN: "DE12500105170648489890", OpenAmount: true},
+			want: "bank://singlepaymentsepa?name=Spende&iban=DE12500105170648489890",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("payload=%q\nwant   %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (BezahlCodePayload{Name: "x", IBAN: "DE12500105170648489890"}).Build(); err == nil {
+		t.Fatalf("expected error for missing amount")
+	}
+
+	reason := strings.Repeat("x", validate.BezahlCodeReasonMax)
+	p := BezahlCodePayload{Name: "x", IBAN: "DE12500105170648489890", AmountCents: 1, Reason: reason, Period: "1M"}
+	if got, err := p.Build(); err != nil || !strings.Contains(got, "reason="+reason+"&") {
+		t.Fatalf("expected the full %d-character reason, got %q, %v", len(reason), got, err)
+	}
+	p.Reason += "x"
+	if _, err := p.Build(); err == nil {
+		t.Fatalf("expected error for a reason over %d characters", validate.BezahlCodeReasonMax)
+	}
+}
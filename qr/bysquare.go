@@ -0,0 +1,200 @@
+package qr
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PAY by square header: bysquare type 0 (PAY), version 1.1.0, document
+// type 0, reserved 0.
+const (
+	bySquareType    = 0
+	bySquareVersion = 1
+)
+
+var (
+	bySquareEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+	reBySquareAmount = regexp.MustCompile(`^(\d{1,13})(\.\d{1,2})?$`)
+)
+
+// PayBySquarePayload holds a single payment order of a Slovak PAY by square
+// document. DueDate is YYYY-MM-DD; OpenAmount leaves the amount empty.
+type PayBySquarePayload struct {
+	IBAN            string
+	BIC             string
+	AmountCents     int64
+	OpenAmount      bool
+	Currency        string
+	DueDate         string
+	VariableSymbol  string
+	ConstantSymbol  string
+	SpecificSymbol  string
+	Reference       string
+	Note            string
+	BeneficiaryName string
+	AddressLine1    string
+	AddressLine2    string
+}
+
+// fields returns the tab-separated data model: invoice ID, one payment order
+// with one bank account, no standing order or direct debit extension, then
+// the beneficiary block added in version 1.1.0.
+func (p PayBySquarePayload) fields() []string {
+	amount := ""
+	if !p.OpenAmount {
+		amount = fmt.Sprintf("%d.%02d", p.AmountCents/100, p.AmountCents%100)
+	}
+	return []string{
+		"",  // invoice ID
+		"1", // payments
+		"1", // payment options: payment order
+		amount,
+		p.Currency,
+		strings.ReplaceAll(p.DueDate, "-", ""),
+		p.VariableSymbol,
+		p.ConstantSymbol,
+		p.SpecificSymbol,
+		p.Reference,
+		p.Note,
+		"1", // bank accounts
+		p.IBAN,
+		p.BIC,
+		"0", // standing order extension
+		"0", // direct debit extension
+		p.BeneficiaryName,
+		p.AddressLine1,
+		p.AddressLine2,
+	}
+}
+
+func (p PayBySquarePayload) Build() (string, error) {
+	if p.IBAN == "" || p.Currency == "" {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.OpenAmount {
+		if p.AmountCents != 0 {
+			return "", fmt.Errorf("open amount payload must not carry an amount")
+		}
+	} else if p.AmountCents <= 0 {
+		return "", fmt.Errorf("missing required fields")
+	}
+	fields := p.fields()
+	for _, f := range fields {
+		if strings.ContainsAny(f, "\t\r\n") {
+			return "", fmt.Errorf("fields must not contain tabs or line breaks")
+		}
+	}
+
+	// CRC32 of the data model, prepended little-endian.
+	model := []byte(strings.Join(fields, "\t"))
+	data := binary.LittleEndian.AppendUint32(nil, crc32.ChecksumIEEE(model))
+	data = append(data, model...)
+	if len(data) > 0xFFFF {
+		return "", fmt.Errorf("payload too large")
+	}
+
+	out := []byte{bySquareType<<4 | bySquareVersion, 0}
+	out = binary.LittleEndian.AppendUint16(out, uint16(len(data)))
+	out = append(out, lzmaCompress(data)...)
+	return bySquareEncoding.EncodeToString(out), nil
+}
+
+// ParsePayBySquare decodes a PAY by square string back into its payment
+// order. Only documents with exactly one payment order and one bank
+// account, without standing order or direct debit extensions, are accepted.
+func ParsePayBySquare(s string) (*PayBySquarePayload, error) {
+	raw, err := bySquareEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base32hex encoding")
+	}
+	if len(raw) < 4 {
+		return nil, fmt.Errorf("payload too short")
+	}
+	if raw[0]>>4 != bySquareType {
+		return nil, fmt.Errorf("unsupported bysquare type %d", raw[0]>>4)
+	}
+	if v := raw[0] & 0x0F; v > bySquareVersion {
+		return nil, fmt.Errorf("unsupported bysquare version %d", v)
+	}
+	size := int(binary.LittleEndian.Uint16(raw[2:4]))
+	data, err := lzmaDecompress(raw[4:], size)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, fmt.Errorf("payload too short")
+	}
+	model := data[4:]
+	if crc32.ChecksumIEEE(model) != binary.LittleEndian.Uint32(data[:4]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+
+	f := strings.Split(string(model), "\t")
+	// Version 1.0.0 documents end before the beneficiary block.
+	for len(f) < 19 {
+		f = append(f, "")
+	}
+	if len(f) > 19 {
+		return nil, fmt.Errorf("unsupported document: %d fields", len(f))
+	}
+	if f[1] != "1" {
+		return nil, fmt.Errorf("unsupported number of payments: %s", f[1])
+	}
+	if f[2] != "1" {
+		return nil, fmt.Errorf("unsupported payment options: %s", f[2])
+	}
+	if f[11] != "1" {
+		return nil, fmt.Errorf("unsupported number of bank accounts: %s", f[11])
+	}
+	if f[14] != "0" || f[15] != "0" {
+		return nil, fmt.Errorf("standing order and direct debit extensions are not supported")
+	}
+
+	p := &PayBySquarePayload{
+		Currency:        f[4],
+		VariableSymbol:  f[6],
+		ConstantSymbol:  f[7],
+		SpecificSymbol:  f[8],
+		Reference:       f[9],
+		Note:            f[10],
+		IBAN:            f[12],
+		BIC:             f[13],
+		BeneficiaryName: f[16],
+		AddressLine1:    f[17],
+		AddressLine2:    f[18],
+	}
+	if f[3] == "" {
+		p.OpenAmount = true
+	} else if p.AmountCents, err = parseBySquareAmount(f[3]); err != nil {
+		return nil, err
+	}
+	if d := f[5]; d != "" {
+		if len(d) != 8 {
+			return nil, fmt.Errorf("invalid due date: %s", d)
+		}
+		p.DueDate = d[:4] + "-" + d[4:6] + "-" + d[6:]
+	}
+	return p, nil
+}
+
+func parseBySquareAmount(s string) (int64, error) {
+	m := reBySquareAmount.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid amount: %s", s)
+	}
+	whole, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %s", s)
+	}
+	frac := strings.TrimPrefix(m[2], ".")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	return whole*100 + f, nil
+}
@@ -0,0 +1,81 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPayBySquareRoundTrip(t *testing.T) {
+	tests := []PayBySquarePayload{
+		{
+			IBAN:            "SK3112000000198742637541",
+			BIC:             "TATRSKBX",
+			AmountCents:     12550,
+			Currency:        "EUR",
+			DueDate:         "2026-11-30",
+			VariableSymbol:  "2026001234",
+			ConstantSymbol:  "0308",
+			SpecificSymbol:  "42",
+			Note:            "Faktúra 2026/17 – ďakujeme",
+			BeneficiaryName: "Príklad s.r.o.",
+			AddressLine1:    "Hlavná 12",
+			AddressLine2:    "811 01 Bratislava",
+		},
+		{IBAN: "SK3112000000198742637541", OpenAmount: true, Currency: "EUR", Reference: "RF18539007547034"},
+	}
+	for _, want := range tests {
+		s, err := want.Build()
+		if err != nil {
+			t.Fatalf("build: %v", err)
+		}
+		if strings.Trim(s, "0123456789ABCDEFGHIJKLMNOPQRSTUV") != "" {
+			t.Fatalf("payload is not base32hex: %q", s)
+		}
+		got, err := ParsePayBySquare(s)
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		if *got != want {
+			t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", *got, want)
+		}
+	}
+}
+
+func TestParsePayBySquareRejectsCorruption(t *testing.T) {
+	s, err := PayBySquarePayload{IBAN: "SK3112000000198742637541", AmountCents: 100, Currency: "EUR"}.Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	raw, _ := bySquareEncoding.DecodeString(s)
+	raw[0] = 0x10
+	if _, err := ParsePayBySquare(bySquareEncoding.EncodeToString(raw)); err == nil || !strings.Contains(err.Error(), "bysquare type") {
+		t.Fatalf("expected bysquare type error, got %v", err)
+	}
+	raw[0] = 0x01
+	raw[len(raw)/2] ^= 0x55
+	if _, err := ParsePayBySquare(bySquareEncoding.EncodeToString(raw)); err == nil {
+		t.Fatalf("expected error for corrupted payload")
+	}
+	if _, err := ParsePayBySquare("not base32"); err == nil {
+		t.Fatalf("expected encoding error")
+	}
+}
+
+func TestLZMARoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		{},
+		[]byte("a"),
+		bytes.Repeat([]byte("ab"), 400),
+		[]byte("\t1\t1\t10.00\tEUR\t\t123\t\t\t\t\t1\tSK3112000000198742637541\t\t0\t0\tExample\t\t"),
+	}
+	for _, in := range inputs {
+		out, err := lzmaDecompress(lzmaCompress(in), len(in))
+		if err != nil {
+			t.Fatalf("decompress %q: %v", in, err)
+		}
+		if !bytes.Equal(out, in) {
+			t.Fatalf("round trip mismatch for %q: %q", in, out)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package qr
+
+import "testing"
+
+func TestEncodeCode128(t *testing.T) {
+	// Start C, 12, 34, check (105 + 12 + 2*34) % 103 = 82, stop.
+	modules, err := EncodeCode128("1234")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "11010011100" + "10110011100" + "10001011000" + "10010011110" + "1100011101011"
+	if got := bitString(modules); got != want {
+		t.Fatalf("modules=%s\nwant    %s", got, want)
+	}
+
+	// Odd length and letters fall back to code set B.
+	modules, err = EncodeCode128("A1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := bitString(modules[:11]); got != "11010010000" || len(modules) != 4*11+13 {
+		t.Fatalf("unexpected code set B encoding %s", bitString(modules))
+	}
+	if _, err := EncodeCode128("ä"); err == nil {
+		t.Fatalf("expected error for non-ASCII data")
+	}
+}
+
+func bitString(modules []bool) string {
+	b := make([]byte, len(modules))
+	for i, m := range modules {
+		b[i] = '0'
+		if m {
+			b[i] = '1'
+		}
+	}
+	return string(b)
+}
@@ -2,33 +2,121 @@ package qr
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+// EPC versions supported by the payload builder.
+const (
+	EPCVersion001 = "001"
+	EPCVersion002 = "002"
+)
+
+// EPC identification codes: regular and instant SEPA Credit Transfer.
+const (
+	EPCIdentSCT  = "SCT"
+	EPCIdentINST = "INST"
 )
 
+// EPCPayload holds the fields of an EPC069-12 SEPA Credit Transfer payload.
+// Text fields are UTF-8; Build encodes them in Charset (0 means UTF-8).
+// OpenAmount leaves the amount line empty so the payer enters it; it
+// requires AmountCents to be zero.
+type EPCPayload struct {
+	Version        string
+	Charset        int
+	Identification string
+	BIC            string
+	Name           string
+	IBAN           string
+	AmountCents    int64
+	OpenAmount     bool
+	Purpose        string
+	RemittanceRef  string
+	RemittanceText string
+	Information    string
+}
+
 func BuildEPCPayload(name, iban, bic string, amountCents int64, purpose, remittanceRef, remittanceText, info string) (string, error) {
-	if name == "" || iban == "" || bic == "" || amountCents <= 0 {
+	return EPCPayload{
+		Version:        EPCVersion001,
+		BIC:            bic,
+		Name:           name,
+		IBAN:           iban,
+		AmountCents:    amountCents,
+		Purpose:        purpose,
+		RemittanceRef:  remittanceRef,
+		RemittanceText: remittanceText,
+		Information:    info,
+	}.Build()
+}
+
+func (p EPCPayload) Build() (string, error) {
+	version := p.Version
+	if version == "" {
+		version = EPCVersion001
+	}
+	if version != EPCVersion001 && version != EPCVersion002 {
+		return "", fmt.Errorf("unsupported epc version")
+	}
+	cs := p.Charset
+	if cs == 0 {
+		cs = charset.UTF8
+	}
+	if !charset.Valid(cs) {
+		return "", fmt.Errorf("unsupported charset")
+	}
+	ident := p.Identification
+	if ident == "" {
+		ident = EPCIdentSCT
+	}
+	if ident != EPCIdentSCT && ident != EPCIdentINST {
+		return "", fmt.Errorf("unsupported identification code")
+	}
+	// Version 002 allows an empty BIC; the caller decides whether that is acceptable.
+	if p.Name == "" || p.IBAN == "" || (p.BIC == "" && version == EPCVersion001) {
 		return "", fmt.Errorf("missing required fields")
 	}
-	if remittanceRef != "" && remittanceText != "" {
+	if p.OpenAmount {
+		if p.AmountCents != 0 {
+			return "", fmt.Errorf("open amount payload must not carry an amount")
+		}
+	} else if p.AmountCents <= 0 {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.RemittanceRef != "" && p.RemittanceText != "" {
 		return "", fmt.Errorf("remittance reference and text are mutually exclusive")
 	}
 
-	amountStr := "EUR" + fmt.Sprintf("%d.%02d", amountCents/100, amountCents%100)
+	amountStr := ""
+	if !p.OpenAmount {
+		amountStr = "EUR" + fmt.Sprintf("%d.%02d", p.AmountCents/100, p.AmountCents%100)
+	}
 
 	lines := []string{
 		"BCD",
-		"001",
-		"1",
-		"SCT",
-		bic,
-		name,
-		iban,
+		version,
+		strconv.Itoa(cs),
+		ident,
+		p.BIC,
+		p.Name,
+		p.IBAN,
 		amountStr,
-		purpose,
-		remittanceRef,
-		remittanceText,
-		info,
+		p.Purpose,
+		p.RemittanceRef,
+		p.RemittanceText,
+		p.Information,
 	}
 
-	return strings.Join(lines, "\n"), nil
+	payload, err := charset.Encode(strings.Join(lines, "\n"), cs)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) > validate.EPCMaxPayloadBytes {
+		return "", fmt.Errorf("payload exceeds %d bytes", validate.EPCMaxPayloadBytes)
+	}
+	return payload, nil
 }
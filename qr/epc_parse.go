@@ -0,0 +1,251 @@
+package qr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var (
+	reEPCAmount  = regexp.MustCompile(`^EUR(\d{1,9})(\.\d{1,2})?$`)
+	reEPCPurpose = regexp.MustCompile(`^[A-Z0-9]{4}$`)
+)
+
+// epcFields names the payload lines in order, as used in diagnostics.
+var epcFields = [...]string{
+	"service_tag",
+	"version",
+	"charset",
+	"identification",
+	"bic",
+	"name",
+	"iban",
+	"amount",
+	"purpose",
+	"remittance_reference",
+	"remittance_text",
+	"information",
+}
+
+// EPCLineError describes a problem with one line (1-based) of an EPC payload.
+type EPCLineError struct {
+	Line  int
+	Field string
+	Msg   string
+}
+
+func (e *EPCLineError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("line %d (%s): %s", e.Line, e.Field, e.Msg)
+}
+
+// EPCParseError collects every line-level problem found in a payload.
+type EPCParseError []*EPCLineError
+
+func (e EPCParseError) Error() string {
+	msgs := make([]string, len(e))
+	for i, le := range e {
+		msgs[i] = le.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ParseEPCPayload parses an EPC069-12 payload as it is stored in the QR
+// code (bytes in the charset announced on line 3, LF or CRLF separated).
+// Omitted trailing lines are treated as empty. All problems are reported
+// together as an EPCParseError.
+func ParseEPCPayload(payload string) (*EPCPayload, error) {
+	raw := strings.Split(payload, "\n")
+	for i, l := range raw {
+		raw[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	var errs EPCParseError
+	fail := func(line int, format string, args ...any) {
+		field := ""
+		if line >= 1 && line <= len(epcFields) {
+			field = epcFields[line-1]
+		}
+		errs = append(errs, &EPCLineError{Line: line, Field: field, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if len(raw) > len(epcFields) {
+		for i := len(epcFields); i < len(raw); i++ {
+			fail(i+1, "unexpected line")
+		}
+		raw = raw[:len(epcFields)]
+	}
+	if len(raw) < 7 {
+		fail(len(raw)+1, "payload ends before the iban line")
+		return nil, errs
+	}
+	lines := make([]string, len(epcFields))
+	copy(lines, raw)
+
+	if lines[0] != "BCD" {
+		fail(1, "expected service tag BCD, got %q", lines[0])
+	}
+	version := lines[1]
+	if version != EPCVersion001 && version != EPCVersion002 {
+		fail(2, "unsupported version %q", version)
+	}
+	cs, err := strconv.Atoi(lines[2])
+	if err != nil || !charset.Valid(cs) || len(lines[2]) != 1 {
+		fail(3, "unsupported charset %q", lines[2])
+		return nil, errs
+	}
+	ident := lines[3]
+	if ident != EPCIdentSCT && ident != EPCIdentINST {
+		fail(4, "unsupported identification code %q", ident)
+	}
+
+	// Decode the text lines from the announced charset.
+	for i := 4; i < len(lines); i++ {
+		text, err := charset.Decode(lines[i], cs)
+		if err != nil {
+			fail(i+1, "%v", err)
+			continue
+		}
+		lines[i] = text
+	}
+
+	p := &EPCPayload{
+		Version:        version,
+		Charset:        cs,
+		Identification: ident,
+		BIC:            lines[4],
+		Name:           lines[5],
+		IBAN:           lines[6],
+		Purpose:        lines[8],
+		RemittanceRef:  lines[9],
+		RemittanceText: lines[10],
+		Information:    lines[11],
+	}
+
+	if p.BIC == "" {
+		if version == EPCVersion001 {
+			fail(5, "bic is required in version 001")
+		}
+	} else if !reEPCBIC.MatchString(p.BIC) {
+		fail(5, "invalid bic %q", p.BIC)
+	}
+	if p.Name == "" {
+		fail(6, "name is required")
+	} else if utf8.RuneCountInString(p.Name) > 70 {
+		fail(6, "name exceeds 70 characters")
+	}
+	if p.IBAN == "" {
+		fail(7, "iban is required")
+	} else if !validEPCIBAN(p.IBAN) {
+		fail(7, "invalid iban %q", p.IBAN)
+	}
+	if amount := lines[7]; amount != "" {
+		cents, ok := parseEPCAmount(amount)
+		if !ok {
+			fail(8, "invalid amount %q", amount)
+		}
+		p.AmountCents = cents
+	} else {
+		p.OpenAmount = true
+	}
+	if p.Purpose != "" && !reEPCPurpose.MatchString(p.Purpose) {
+		fail(9, "invalid purpose %q", p.Purpose)
+	}
+	if utf8.RuneCountInString(p.RemittanceRef) > 35 {
+		fail(10, "remittance reference exceeds 35 characters")
+	}
+	if utf8.RuneCountInString(p.RemittanceText) > 140 {
+		fail(11, "remittance text exceeds 140 characters")
+	}
+	if p.RemittanceRef != "" && p.RemittanceText != "" {
+		fail(11, "remittance reference and text are mutually exclusive")
+	}
+	if utf8.RuneCountInString(p.Information) > 70 {
+		fail(12, "information exceeds 70 characters")
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Line < errs[j].Line })
+		return nil, errs
+	}
+	return p, nil
+}
+
+// CheckEPCRoundTrip parses payload and verifies that Build reproduces it
+// byte for byte. A mismatch is reported on the first differing line.
+func CheckEPCRoundTrip(payload string) (*EPCPayload, error) {
+	p, err := ParseEPCPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	rebuilt, err := p.Build()
+	if err != nil {
+		return p, fmt.Errorf("round trip build failed: %w", err)
+	}
+	if rebuilt == payload {
+		return p, nil
+	}
+	got := strings.Split(payload, "\n")
+	want := strings.Split(rebuilt, "\n")
+	for i := range want {
+		field := epcFields[i]
+		if i >= len(got) {
+			return p, EPCParseError{{Line: i + 1, Field: field, Msg: "round trip mismatch: line omitted, builder emits all 12 lines"}}
+		}
+		if got[i] != want[i] {
+			return p, EPCParseError{{Line: i + 1, Field: field, Msg: fmt.Sprintf("round trip mismatch: payload has %q, builder produces %q", got[i], want[i])}}
+		}
+	}
+	return p, EPCParseError{{Line: len(want) + 1, Msg: "round trip mismatch: unexpected trailing data"}}
+}
+
+func parseEPCAmount(s string) (int64, bool) {
+	m := reEPCAmount.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	whole, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	frac := strings.TrimPrefix(m[2], ".")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	cents := whole*100 + f
+	if cents <= 0 {
+		return 0, false
+	}
+	return cents, true
+}
+
+// The parser only checks the structure of IBAN and BIC so that a payload
+// parses the same regardless of the server's validation settings.
+var (
+	reEPCBIC  = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+	reEPCIBAN = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+)
+
+// validEPCIBAN checks the IBAN format and its ISO 7064 mod 97-10 check.
+func validEPCIBAN(iban string) bool {
+	if !reEPCIBAN.MatchString(iban) {
+		return false
+	}
+	mod := 0
+	for _, c := range iban[4:] + iban[:4] {
+		if c <= '9' {
+			mod = (mod*10 + int(c-'0')) % 97
+		} else {
+			mod = (mod*100 + int(c-'A') + 10) % 97
+		}
+	}
+	return mod == 1
+}
@@ -0,0 +1,126 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/charset"
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func TestParseEPCPayload_RoundTripAllCombinations(t *testing.T) {
+	names := map[int]string{
+		charset.UTF8:       "Сервис Müller",
+		charset.ISO8859_1:  "Müller François",
+		charset.ISO8859_2:  "Łódź Žilina",
+		charset.ISO8859_4:  "Ķekava Ģirts",
+		charset.ISO8859_5:  "Сервис Москва",
+		charset.ISO8859_7:  "Αθηνα Tech",
+		charset.ISO8859_10: "Ŋaaŋ Ålesund",
+		charset.ISO8859_15: "Œuvre Šimon",
+	}
+	for _, version := range []string{EPCVersion001, EPCVersion002} {
+		for cs, name := range names {
+			for _, ident := range []string{EPCIdentSCT, EPCIdentINST} {
+				want := EPCPayload{
+					Version:        version,
+					Charset:        cs,
+					Identification: ident,
+					BIC:            "INGDDEFFXXX",
+					Name:           name,
+					IBAN:           "DE12500105170648489890",
+					AmountCents:    4990,
+					Purpose:        "GDDS",
+					RemittanceText: "Invoice 0001",
+					Information:    "Thanks",
+				}
+				if version == EPCVersion002 {
+					want.BIC = ""
+				}
+				payload, err := want.Build()
+				if err != nil {
+					t.Fatalf("build %s/%d/%s: %v", version, cs, ident, err)
+				}
+				got, err := CheckEPCRoundTrip(payload)
+				if err != nil {
+					t.Fatalf("round trip %s/%d/%s: %v", version, cs, ident, err)
+				}
+				if *got != want {
+					t.Fatalf("parsed mismatch %s/%d/%s:\ngot  %+v\nwant %+v", version, cs, ident, *got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestParseEPCPayload_LineDiagnostics(t *testing.T) {
+	payload := "BCD\n003\n1\nSCT\nBAD\n\nDE00500105170648489890\nEUR0.00\ngd\nREF\nTEXT\n"
+	_, err := ParseEPCPayload(payload)
+	var perr EPCParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected EPCParseError, got %v", err)
+	}
+	wantLines := []int{2, 5, 6, 7, 8, 9, 11}
+	if len(perr) != len(wantLines) {
+		t.Fatalf("expected %d line errors, got %d: %v", len(wantLines), len(perr), perr)
+	}
+	for i, line := range wantLines {
+		if perr[i].Line != line {
+			t.Fatalf("error %d: line=%d want %d (%v)", i, perr[i].Line, line, perr[i])
+		}
+	}
+}
+
+func TestParseEPCPayload_Truncated(t *testing.T) {
+	_, err := ParseEPCPayload("BCD\n001\n1\nSCT")
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("expected line 5 error, got %v", err)
+	}
+}
+
+func TestCheckEPCRoundTrip_ReportsMismatch(t *testing.T) {
+	payload := "BCD\r\n001\r\n1\r\nSCT\r\nINGDDEFFXXX\r\nExample GmbH\r\nDE12500105170648489890\r\nEUR1\r\nGDDS"
+	p, err := ParseEPCPayload(payload)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if p.AmountCents != 100 {
+		t.Fatalf("amount cents=%d want 100", p.AmountCents)
+	}
+	_, err = CheckEPCRoundTrip(payload)
+	var perr EPCParseError
+	if !errors.As(err, &perr) || perr[0].Line != 1 {
+		t.Fatalf("expected mismatch on line 1 (CRLF), got %v", err)
+	}
+
+	_, err = CheckEPCRoundTrip(strings.ReplaceAll(payload, "\r", ""))
+	if !errors.As(err, &perr) || perr[0].Line != 8 {
+		t.Fatalf("expected mismatch on amount line, got %v", err)
+	}
+}
+
+func TestCheckEPCRoundTrip_OpenAmount(t *testing.T) {
+	payload := "BCD\n002\n1\nSCT\n\nExample e.V.\nDE12500105170648489890\n\nCHAR\n\nDonation\n"
+	p, err := CheckEPCRoundTrip(payload)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	if !p.OpenAmount || p.AmountCents != 0 {
+		t.Fatalf("expected open amount, got %+v", *p)
+	}
+}
+
+func TestParseEPCPayload_IgnoresValidationSettings(t *testing.T) {
+	// Method 00 rejects the account number; the parser must not care.
+	validate.SetBICDirectory(validate.NewBICDirectory([]validate.BankEntry{{Country: "DE", BankCode: "50010517", CheckMethod: "00"}}))
+	defer validate.SetBICDirectory(nil)
+	validate.SetAccountCheck(true)
+	defer validate.SetAccountCheck(false)
+	if validate.ValidIBAN("DE12500105170648489890") {
+		t.Fatalf("expected the account check to reject the IBAN")
+	}
+	if _, err := ParseEPCPayload("BCD\n002\n1\nSCT\nINGDDEFFXXX\nExample GmbH\nDE12500105170648489890\nEUR1.00\n"); err != nil {
+		t.Fatalf("parse depends on validation settings: %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package qr
 import (
 	"strings"
 	"testing"
+
+	"github.com/safe-cap/sepaqx/charset"
 )
 
 func TestBuildEPCPayload_OrderAndEmptyLines(t *testing.T) {
@@ -54,3 +56,98 @@ func TestBuildEPCPayload_MutualExclusion(t *testing.T) {
 		t.Fatalf("expected error when remittance ref and text are both set")
 	}
 }
+
+func TestEPCPayloadBuild_Version002WithoutBIC(t *testing.T) {
+	payload, err := EPCPayload{
+		Version:     EPCVersion002,
+		Name:        "Example GmbH",
+		IBAN:        "DE12500105170648489890",
+		AmountCents: 100,
+		Purpose:     "GDDS",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(payload, "\n")
+	if lines[1] != "002" || lines[4] != "" {
+		t.Fatalf("unexpected version/bic lines: %q %q", lines[1], lines[4])
+	}
+
+	_, err = EPCPayload{
+		Version:     EPCVersion001,
+		Name:        "Example GmbH",
+		IBAN:        "DE12500105170648489890",
+		AmountCents: 100,
+	}.Build()
+	if err == nil {
+		t.Fatalf("expected error for version 001 without BIC")
+	}
+}
+
+func TestEPCPayloadBuild_Charset(t *testing.T) {
+	payload, err := EPCPayload{
+		Charset:     charset.ISO8859_1,
+		BIC:         "INGDDEFFXXX",
+		Name:        "Müller",
+		IBAN:        "DE12500105170648489890",
+		AmountCents: 100,
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(payload, "\n")
+	if lines[2] != "2" {
+		t.Fatalf("unexpected charset line: %q", lines[2])
+	}
+	if lines[5] != "M\xfcller" {
+		t.Fatalf("expected ISO-8859-1 encoded name, got %q", lines[5])
+	}
+
+	_, err = EPCPayload{
+		Charset:     charset.ISO8859_1,
+		BIC:         "INGDDEFFXXX",
+		Name:        "Жук",
+		IBAN:        "DE12500105170648489890",
+		AmountCents: 100,
+	}.Build()
+	if err == nil {
+		t.Fatalf("expected error for characters outside the charset")
+	}
+}
+
+func TestEPCPayloadBuild_OpenAmount(t *testing.T) {
+	payload, err := EPCPayload{
+		BIC:        "INGDDEFFXXX",
+		Name:       "Example e.V.",
+		IBAN:       "DE12500105170648489890",
+		OpenAmount: true,
+		Purpose:    "CHAR",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(payload, "\n")
+	if len(lines) != 12 || lines[7] != "" || lines[8] != "CHAR" {
+		t.Fatalf("expected empty amount line, got %q", payload)
+	}
+
+	_, err = EPCPayload{
+		BIC:         "INGDDEFFXXX",
+		Name:        "Example e.V.",
+		IBAN:        "DE12500105170648489890",
+		AmountCents: 100,
+		OpenAmount:  true,
+	}.Build()
+	if err == nil {
+		t.Fatalf("expected error for open amount with an amount")
+	}
+
+	_, err = EPCPayload{
+		BIC:  "INGDDEFFXXX",
+		Name: "Example e.V.",
+		IBAN: "DE12500105170648489890",
+	}.Build()
+	if err == nil {
+		t.Fatalf("expected error for a zero amount without open amount")
+	}
+}
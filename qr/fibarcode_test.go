@@ -0,0 +1,52 @@
+package qr
+
+import "testing"
+
+func TestFIBarcodePayloadBuild(t *testing.T) {
+	tests := []struct {
+		name string
+		p    FIBarcodePayload
+		want string
+	}{
+		{
+			name: "version4",
+			p:    FIBarcodePayload{IBAN: "FI7944052020036082", AmountCents: 488315, Reference: "868516259619897", DueDate: "2010-06-12"},
+			want: "479440520200360820048831500000000868516259619897100612",
+		},
+		{
+			name: "version5",
+			p:    FIBarcodePayload{IBAN: "FI7944052020036082", AmountCents: 488315, Reference: "RF09868516259619897", DueDate: "2010-06-12"},
+			want: "579440520200360820048831509000000868516259619897100612",
+		},
+		{
+			name: "no_amount_no_due_date",
+			p:    FIBarcodePayload{IBAN: "FI7944052020036082", Reference: "12345672"},
+			want: "479440520200360820000000000000000000000012345672000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || len(got) != 54 {
+				t.Fatalf("payload=%q\nwant   %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (FIBarcodePayload{IBAN: "DE12500105170648489890", AmountCents: 1, Reference: "12345672"}).Build(); err == nil {
+		t.Fatalf("expected error for non-FI IBAN")
+	}
+	if _, err := (FIBarcodePayload{IBAN: "FI7944052020036082", AmountCents: 100000000, Reference: "12345672"}).Build(); err == nil {
+		t.Fatalf("expected error for amount over 999999.99")
+	}
+}
+
+func TestMakeBarcodeFI(t *testing.T) {
+	png, err := MakeBarcode("fi_barcode", "479440520200360820048831500000000868516259619897100612", Options{Size: 512})
+	if err != nil || len(png) == 0 {
+		t.Fatalf("MakeBarcode: %v", err)
+	}
+}
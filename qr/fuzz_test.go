@@ -24,3 +24,18 @@ func FuzzHexColors(f *testing.F) {
 		_, _, _ = parseBgColor(s)
 	})
 }
+
+func FuzzParseEPCPayload(f *testing.F) {
+	f.Add("BCD\n001\n1\nSCT\nINGDDEFFXXX\nExample GmbH\nDE12500105170648489890\nEUR49.90\nGDDS\n\nInvoice\n")
+	f.Add("BCD\n002\n2\nINST\n\nM\xfcller\nDE12500105170648489890\n\n\nRF18539007547034\n\n")
+	f.Add("BCD\n001\n9\nSCT")
+	f.Fuzz(func(t *testing.T, payload string) {
+		p, err := ParseEPCPayload(payload)
+		if err != nil {
+			return
+		}
+		if _, err := p.Build(); err != nil && p.AmountCents > 0 {
+			t.Fatalf("parsed payload does not rebuild: %v", err)
+		}
+	})
+}
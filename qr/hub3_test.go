@@ -0,0 +1,60 @@
+package qr
+
+import (
+	"testing"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+func TestHUB3PayloadBuild(t *testing.T) {
+	payload, err := HUB3Payload{
+		Currency:    "EUR",
+		AmountCents: 12550,
+		PayerName:   "Ivan Horvat",
+		Name:        "Primatelj d.o.o.",
+		Street:      "Ilica 1",
+		Town:        "10000 Zagreb",
+		IBAN:        "HR1210010051863000160",
+		Reference:   "HR011234-5678",
+		PurposeCode: "OTHR",
+		Description: "Račun 17",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text, err := charset.Decode(payload, charset.ISO8859_2)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	want := "HRVHUB30\nEUR\n000000000012550\nIvan Horvat\n\n\nPrimatelj d.o.o.\nIlica 1\n10000 Zagreb\nHR1210010051863000160\nHR01\n1234-5678\nOTHR\nRačun 17\n"
+	if text != want {
+		t.Fatalf("payload=%q\nwant   %q", text, want)
+	}
+	if len(payload) != len(want)-1 {
+		t.Fatalf("expected single-byte č, got %d bytes", len(payload))
+	}
+
+	if _, err := (HUB3Payload{Currency: "USD", AmountCents: 1, Name: "x", IBAN: "HR1210010051863000160", Reference: "HR99", Description: "x"}).Build(); err == nil {
+		t.Fatalf("expected error for USD")
+	}
+	if _, err := (HUB3Payload{Currency: "EUR", AmountCents: 1, Name: "x", IBAN: "HR1210010051863000160", Reference: "HR99", Description: "Opis plaćanja koji je predugačak za HUB3"}).Build(); err == nil {
+		t.Fatalf("expected error for long description")
+	}
+}
+
+func TestMakeBarcodeHUB3(t *testing.T) {
+	payload, err := HUB3Payload{Currency: "EUR", AmountCents: 100, Name: "x", IBAN: "HR1210010051863000160", Reference: "HR99", Description: "x"}.Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if SchemeIsQR("hr_hub3") || !SchemeIsQR("epc_sct") || SchemeAllowsLogo("hr_hub3") {
+		t.Fatalf("hr_hub3 must render as a non-QR symbol without logo")
+	}
+	png, err := MakeBarcode("hr_hub3", payload, Options{Size: 512})
+	if err != nil || len(png) == 0 {
+		t.Fatalf("MakeBarcode: %v", err)
+	}
+	if _, err := MakeBarcode("epc_sct", payload, Options{Size: 512}); err == nil {
+		t.Fatalf("expected error for QR scheme")
+	}
+}
@@ -0,0 +1,537 @@
+package qr
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Raw LZMA1 stream with the fixed properties PAY by square uses: lc=3, lp=0,
+// pb=2 and a 128 KiB dictionary. Payloads are a few hundred bytes, so the
+// encoder uses a plain greedy search instead of a hash-chain match finder.
+const (
+	lzmaLC       = 3
+	lzmaPB       = 2
+	lzmaDictSize = 1 << 17
+
+	lzmaStates       = 12
+	lzmaPosStates    = 1 << lzmaPB
+	lzmaMinMatch     = 2
+	lzmaMaxMatch     = 273
+	lzmaEndPosModel  = 14
+	lzmaFullDistance = 128
+	lzmaEndMarker    = 0xFFFFFFFF
+
+	lzmaProbBits = 11
+	lzmaProbInit = 1 << (lzmaProbBits - 1)
+	lzmaMoveBits = 5
+	lzmaTopValue = 1 << 24
+)
+
+type lzmaLenModel struct {
+	choice  uint16
+	choice2 uint16
+	low     [lzmaPosStates][1 << 3]uint16
+	mid     [lzmaPosStates][1 << 3]uint16
+	high    [1 << 8]uint16
+}
+
+// lzmaModel holds the adaptive probabilities shared by encoder and decoder.
+type lzmaModel struct {
+	literal    [0x300 << lzmaLC]uint16
+	isMatch    [lzmaStates][lzmaPosStates]uint16
+	isRep      [lzmaStates]uint16
+	isRepG0    [lzmaStates]uint16
+	isRepG1    [lzmaStates]uint16
+	isRepG2    [lzmaStates]uint16
+	isRep0Long [lzmaStates][lzmaPosStates]uint16
+	posSlot    [4][1 << 6]uint16
+	posSpec    [1 + lzmaFullDistance - lzmaEndPosModel]uint16
+	align      [1 << 4]uint16
+	length     lzmaLenModel
+	repLength  lzmaLenModel
+}
+
+func newLZMAModel() *lzmaModel {
+	m := &lzmaModel{}
+	initProbs(m.literal[:])
+	for i := range m.isMatch {
+		initProbs(m.isMatch[i][:])
+		initProbs(m.isRep0Long[i][:])
+	}
+	initProbs(m.isRep[:])
+	initProbs(m.isRepG0[:])
+	initProbs(m.isRepG1[:])
+	initProbs(m.isRepG2[:])
+	for i := range m.posSlot {
+		initProbs(m.posSlot[i][:])
+	}
+	initProbs(m.posSpec[:])
+	initProbs(m.align[:])
+	for _, l := range []*lzmaLenModel{&m.length, &m.repLength} {
+		l.choice, l.choice2 = lzmaProbInit, lzmaProbInit
+		for i := range l.low {
+			initProbs(l.low[i][:])
+			initProbs(l.mid[i][:])
+		}
+		initProbs(l.high[:])
+	}
+	return m
+}
+
+func initProbs(p []uint16) {
+	for i := range p {
+		p[i] = lzmaProbInit
+	}
+}
+
+func (m *lzmaModel) literalProbs(prev byte) []uint16 {
+	ctx := int(prev) >> (8 - lzmaLC)
+	return m.literal[ctx*0x300 : (ctx+1)*0x300]
+}
+
+func stateAfterLiteral(s int) int {
+	switch {
+	case s < 4:
+		return 0
+	case s < 10:
+		return s - 3
+	default:
+		return s - 6
+	}
+}
+
+func stateAfter(s, ifLiteralState, otherwise int) int {
+	if s < 7 {
+		return ifLiteralState
+	}
+	return otherwise
+}
+
+func lenToPosState(length int) int {
+	if l := length - lzmaMinMatch; l < 3 {
+		return l
+	}
+	return 3
+}
+
+func posSlotOf(dist uint32) uint32 {
+	if dist < 4 {
+		return dist
+	}
+	n := uint32(bits.Len32(dist) - 1)
+	return 2*n + (dist>>(n-1))&1
+}
+
+// rangeEncoder is the LZMA binary arithmetic coder.
+type rangeEncoder struct {
+	low       uint64
+	rng       uint32
+	cache     byte
+	cacheSize int
+	out       []byte
+}
+
+func newRangeEncoder() *rangeEncoder {
+	return &rangeEncoder{rng: 0xFFFFFFFF, cacheSize: 1}
+}
+
+func (e *rangeEncoder) shiftLow() {
+	if uint32(e.low) < 0xFF000000 || e.low>>32 != 0 {
+		carry := byte(e.low >> 32)
+		temp := e.cache
+		for ; e.cacheSize > 0; e.cacheSize-- {
+			e.out = append(e.out, temp+carry)
+			temp = 0xFF
+		}
+		e.cache = byte(e.low >> 24)
+	}
+	e.cacheSize++
+	e.low = (e.low & 0x00FFFFFF) << 8
+}
+
+func (e *rangeEncoder) bit(p *uint16, b uint32) {
+	bound := (e.rng >> lzmaProbBits) * uint32(*p)
+	if b == 0 {
+		e.rng = bound
+		*p += (1<<lzmaProbBits - *p) >> lzmaMoveBits
+	} else {
+		e.low += uint64(bound)
+		e.rng -= bound
+		*p -= *p >> lzmaMoveBits
+	}
+	for e.rng < lzmaTopValue {
+		e.rng <<= 8
+		e.shiftLow()
+	}
+}
+
+func (e *rangeEncoder) direct(v uint32, n int) {
+	for i := n - 1; i >= 0; i-- {
+		e.rng >>= 1
+		if (v>>uint(i))&1 == 1 {
+			e.low += uint64(e.rng)
+		}
+		for e.rng < lzmaTopValue {
+			e.rng <<= 8
+			e.shiftLow()
+		}
+	}
+}
+
+func (e *rangeEncoder) tree(probs []uint16, n int, v uint32) {
+	m := uint32(1)
+	for i := n - 1; i >= 0; i-- {
+		b := (v >> uint(i)) & 1
+		e.bit(&probs[m], b)
+		m = m<<1 | b
+	}
+}
+
+func (e *rangeEncoder) reverseTree(probs []uint16, n int, v uint32) {
+	m := uint32(1)
+	for i := 0; i < n; i++ {
+		b := v & 1
+		e.bit(&probs[m], b)
+		m = m<<1 | b
+		v >>= 1
+	}
+}
+
+func (e *rangeEncoder) flush() []byte {
+	for i := 0; i < 5; i++ {
+		e.shiftLow()
+	}
+	return e.out
+}
+
+func (e *rangeEncoder) length(l *lzmaLenModel, length, posState int) {
+	v := uint32(length - lzmaMinMatch)
+	switch {
+	case v < 8:
+		e.bit(&l.choice, 0)
+		e.tree(l.low[posState][:], 3, v)
+	case v < 16:
+		e.bit(&l.choice, 1)
+		e.bit(&l.choice2, 0)
+		e.tree(l.mid[posState][:], 3, v-8)
+	default:
+		e.bit(&l.choice, 1)
+		e.bit(&l.choice2, 1)
+		e.tree(l.high[:], 8, v-16)
+	}
+}
+
+func (e *rangeEncoder) distance(m *lzmaModel, dist uint32, length int) {
+	slot := posSlotOf(dist)
+	e.tree(m.posSlot[lenToPosState(length)][:], 6, slot)
+	if slot < 4 {
+		return
+	}
+	n := int(slot>>1) - 1
+	base := (2 | slot&1) << uint(n)
+	rest := dist - base
+	if slot < lzmaEndPosModel {
+		e.reverseTree(m.posSpec[base-slot:], n, rest)
+		return
+	}
+	e.direct(rest>>4, n-4)
+	e.reverseTree(m.align[:], 4, rest&0xF)
+}
+
+// lzmaCompress encodes data as a raw LZMA1 stream terminated by the end
+// marker.
+func lzmaCompress(data []byte) []byte {
+	m := newLZMAModel()
+	e := newRangeEncoder()
+	state := 0
+	var reps [4]uint32
+	haveMatch := false
+
+	for pos := 0; pos < len(data); {
+		posState := pos & (lzmaPosStates - 1)
+		bestLen, bestDist := longestMatch(data, pos)
+		repLen := 0
+		if haveMatch {
+			repLen = matchLen(data, pos, int(reps[0])+1)
+		}
+
+		switch {
+		case repLen >= lzmaMinMatch && repLen+1 >= bestLen:
+			e.bit(&m.isMatch[state][posState], 1)
+			e.bit(&m.isRep[state], 1)
+			e.bit(&m.isRepG0[state], 0)
+			e.bit(&m.isRep0Long[state][posState], 1)
+			e.length(&m.repLength, repLen, posState)
+			state = stateAfter(state, 8, 11)
+			pos += repLen
+		case bestLen >= 3:
+			e.bit(&m.isMatch[state][posState], 1)
+			e.bit(&m.isRep[state], 0)
+			e.length(&m.length, bestLen, posState)
+			e.distance(m, uint32(bestDist-1), bestLen)
+			reps = [4]uint32{uint32(bestDist - 1), reps[0], reps[1], reps[2]}
+			haveMatch = true
+			state = stateAfter(state, 7, 10)
+			pos += bestLen
+		default:
+			e.bit(&m.isMatch[state][posState], 0)
+			var prev byte
+			if pos > 0 {
+				prev = data[pos-1]
+			}
+			probs := m.literalProbs(prev)
+			cur := uint32(data[pos])
+			if state >= 7 {
+				matchByte := uint32(data[pos-int(reps[0])-1])
+				symbol, matched := uint32(1), true
+				for i := 7; i >= 0; i-- {
+					b := (cur >> uint(i)) & 1
+					if matched {
+						mb := (matchByte >> uint(i)) & 1
+						e.bit(&probs[(1+mb)<<8+symbol], b)
+						matched = mb == b
+					} else {
+						e.bit(&probs[symbol], b)
+					}
+					symbol = symbol<<1 | b
+				}
+			} else {
+				e.tree(probs, 8, cur)
+			}
+			state = stateAfterLiteral(state)
+			pos++
+		}
+	}
+
+	// End marker: a match of minimum length at distance 0xFFFFFFFF.
+	posState := len(data) & (lzmaPosStates - 1)
+	e.bit(&m.isMatch[state][posState], 1)
+	e.bit(&m.isRep[state], 0)
+	e.length(&m.length, lzmaMinMatch, posState)
+	e.distance(m, lzmaEndMarker, lzmaMinMatch)
+	return e.flush()
+}
+
+// longestMatch returns the longest earlier occurrence of data[pos:] within
+// the dictionary as a length and a 1-based distance.
+func longestMatch(data []byte, pos int) (int, int) {
+	bestLen, bestDist := 0, 0
+	for dist := 1; dist <= pos && dist <= lzmaDictSize; dist++ {
+		if l := matchLen(data, pos, dist); l > bestLen {
+			bestLen, bestDist = l, dist
+			if l == lzmaMaxMatch {
+				break
+			}
+		}
+	}
+	return bestLen, bestDist
+}
+
+func matchLen(data []byte, pos, dist int) int {
+	if dist > pos {
+		return 0
+	}
+	n := 0
+	for pos+n < len(data) && n < lzmaMaxMatch && data[pos+n] == data[pos+n-dist] {
+		n++
+	}
+	return n
+}
+
+type rangeDecoder struct {
+	in   []byte
+	pos  int
+	rng  uint32
+	code uint32
+}
+
+func newRangeDecoder(in []byte) (*rangeDecoder, error) {
+	if len(in) < 5 || in[0] != 0 {
+		return nil, fmt.Errorf("invalid lzma stream")
+	}
+	d := &rangeDecoder{in: in, pos: 5, rng: 0xFFFFFFFF}
+	for _, b := range in[1:5] {
+		d.code = d.code<<8 | uint32(b)
+	}
+	return d, nil
+}
+
+func (d *rangeDecoder) normalize() {
+	if d.rng < lzmaTopValue {
+		d.rng <<= 8
+		var b byte
+		if d.pos < len(d.in) {
+			b = d.in[d.pos]
+		}
+		d.pos++
+		d.code = d.code<<8 | uint32(b)
+	}
+}
+
+func (d *rangeDecoder) bit(p *uint16) uint32 {
+	bound := (d.rng >> lzmaProbBits) * uint32(*p)
+	var b uint32
+	if d.code < bound {
+		d.rng = bound
+		*p += (1<<lzmaProbBits - *p) >> lzmaMoveBits
+	} else {
+		d.code -= bound
+		d.rng -= bound
+		*p -= *p >> lzmaMoveBits
+		b = 1
+	}
+	d.normalize()
+	return b
+}
+
+func (d *rangeDecoder) direct(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		d.rng >>= 1
+		var b uint32
+		if d.code >= d.rng {
+			d.code -= d.rng
+			b = 1
+		}
+		v = v<<1 | b
+		d.normalize()
+	}
+	return v
+}
+
+func (d *rangeDecoder) tree(probs []uint16, n int) uint32 {
+	m := uint32(1)
+	for i := 0; i < n; i++ {
+		m = m<<1 | d.bit(&probs[m])
+	}
+	return m - 1<<uint(n)
+}
+
+func (d *rangeDecoder) reverseTree(probs []uint16, n int) uint32 {
+	m, v := uint32(1), uint32(0)
+	for i := 0; i < n; i++ {
+		b := d.bit(&probs[m])
+		m = m<<1 | b
+		v |= b << uint(i)
+	}
+	return v
+}
+
+func (d *rangeDecoder) length(l *lzmaLenModel, posState int) int {
+	if d.bit(&l.choice) == 0 {
+		return lzmaMinMatch + int(d.tree(l.low[posState][:], 3))
+	}
+	if d.bit(&l.choice2) == 0 {
+		return lzmaMinMatch + 8 + int(d.tree(l.mid[posState][:], 3))
+	}
+	return lzmaMinMatch + 16 + int(d.tree(l.high[:], 8))
+}
+
+func (d *rangeDecoder) distance(m *lzmaModel, length int) uint32 {
+	slot := d.tree(m.posSlot[lenToPosState(length)][:], 6)
+	if slot < 4 {
+		return slot
+	}
+	n := int(slot>>1) - 1
+	dist := (2 | slot&1) << uint(n)
+	if slot < lzmaEndPosModel {
+		return dist + d.reverseTree(m.posSpec[dist-slot:], n)
+	}
+	dist += d.direct(n-4) << 4
+	return dist + d.reverseTree(m.align[:], 4)
+}
+
+// lzmaDecompress decodes a raw LZMA1 stream of size bytes. The stream may
+// end with or without the end marker.
+func lzmaDecompress(in []byte, size int) ([]byte, error) {
+	d, err := newRangeDecoder(in)
+	if err != nil {
+		return nil, err
+	}
+	m := newLZMAModel()
+	out := make([]byte, 0, size)
+	state := 0
+	var reps [4]uint32
+
+	for len(out) < size {
+		if d.pos > len(d.in)+4 {
+			return nil, fmt.Errorf("truncated lzma stream")
+		}
+		posState := len(out) & (lzmaPosStates - 1)
+		if d.bit(&m.isMatch[state][posState]) == 0 {
+			var prev byte
+			if len(out) > 0 {
+				prev = out[len(out)-1]
+			}
+			probs := m.literalProbs(prev)
+			symbol := uint32(1)
+			if state >= 7 {
+				matchByte := uint32(out[len(out)-int(reps[0])-1])
+				for symbol < 0x100 {
+					mb := (matchByte >> 7) & 1
+					matchByte <<= 1
+					b := d.bit(&probs[(1+mb)<<8+symbol])
+					symbol = symbol<<1 | b
+					if mb != b {
+						break
+					}
+				}
+			}
+			for symbol < 0x100 {
+				symbol = symbol<<1 | d.bit(&probs[symbol])
+			}
+			out = append(out, byte(symbol))
+			state = stateAfterLiteral(state)
+			continue
+		}
+
+		var length int
+		if d.bit(&m.isRep[state]) == 1 {
+			if len(out) == 0 {
+				return nil, fmt.Errorf("invalid lzma stream")
+			}
+			if d.bit(&m.isRepG0[state]) == 0 {
+				if d.bit(&m.isRep0Long[state][posState]) == 0 {
+					state = stateAfter(state, 9, 11)
+					out = append(out, out[len(out)-int(reps[0])-1])
+					continue
+				}
+			} else {
+				var dist uint32
+				if d.bit(&m.isRepG1[state]) == 0 {
+					dist = reps[1]
+				} else {
+					if d.bit(&m.isRepG2[state]) == 0 {
+						dist = reps[2]
+					} else {
+						dist = reps[3]
+						reps[3] = reps[2]
+					}
+					reps[2] = reps[1]
+				}
+				reps[1] = reps[0]
+				reps[0] = dist
+			}
+			length = d.length(&m.repLength, posState)
+			state = stateAfter(state, 8, 11)
+		} else {
+			reps[3], reps[2], reps[1] = reps[2], reps[1], reps[0]
+			length = d.length(&m.length, posState)
+			state = stateAfter(state, 7, 10)
+			reps[0] = d.distance(m, length)
+			if reps[0] == lzmaEndMarker {
+				break
+			}
+		}
+		if int(reps[0]) >= len(out) {
+			return nil, fmt.Errorf("invalid lzma stream")
+		}
+		for i := 0; i < length && len(out) < size; i++ {
+			out = append(out, out[len(out)-int(reps[0])-1])
+		}
+	}
+	if len(out) != size {
+		return nil, fmt.Errorf("lzma stream ends after %d of %d bytes", len(out), size)
+	}
+	return out, nil
+}
@@ -0,0 +1,95 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MNBMaxPayloadBytes is the MNB QR limit for the whole payload.
+const MNBMaxPayloadBytes = 345
+
+// MNBPayload holds the fields of a Hungarian MNB QR payment code (version
+// 001, UTF-8). TransferType is HCT or RTP; OpenAmount leaves the amount
+// empty, which only HCT allows.
+type MNBPayload struct {
+	TransferType string
+	BIC          string
+	Name         string
+	IBAN         string
+	AmountCents  int64
+	OpenAmount   bool
+	ValidUntil   time.Time
+	Purpose      string
+	Remittance   string
+	InvoiceID    string
+}
+
+// Build returns the 17 LF-separated fields. Shop, device, customer,
+// transaction, loyalty and NAV identifiers are left empty.
+func (p MNBPayload) Build() (string, error) {
+	if p.BIC == "" || p.Name == "" || p.IBAN == "" || p.ValidUntil.IsZero() {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.TransferType != "HCT" && p.TransferType != "RTP" {
+		return "", fmt.Errorf("unsupported transfer type")
+	}
+
+	amount := ""
+	switch {
+	case p.OpenAmount:
+		if p.AmountCents != 0 || p.TransferType == "RTP" {
+			return "", fmt.Errorf("open amount not allowed")
+		}
+	case p.AmountCents <= 0 || p.AmountCents%100 != 0:
+		return "", fmt.Errorf("invalid amount")
+	default:
+		amount = fmt.Sprintf("HUF%d", p.AmountCents/100)
+	}
+
+	_, offset := p.ValidUntil.Zone()
+	if offset%3600 != 0 || offset < -9*3600 || offset > 9*3600 {
+		return "", fmt.Errorf("unsupported time zone offset")
+	}
+	validity := p.ValidUntil.Format("20060102150405") + fmt.Sprintf("%+d", offset/3600)
+
+	fields := []struct {
+		value string
+		max   int
+	}{
+		{p.TransferType, 3},
+		{"001", 3},
+		{"1", 1},
+		{p.BIC, 11},
+		{p.Name, 70},
+		{p.IBAN, 28},
+		{amount, 15},
+		{validity, 16},
+		{p.Purpose, 4},
+		{p.Remittance, 70},
+		{"", 35}, // shop ID
+		{"", 35}, // merchant device ID
+		{p.InvoiceID, 35},
+		{"", 35}, // customer ID
+		{"", 35}, // credit transaction ID
+		{"", 35}, // loyalty ID
+		{"", 35}, // NAV verification code
+	}
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return "", fmt.Errorf("field exceeds %d characters: %q", f.max, f.value)
+		}
+		if strings.ContainsAny(f.value, "\r\n") {
+			return "", fmt.Errorf("fields must not contain line breaks")
+		}
+		lines[i] = f.value
+	}
+
+	payload := strings.Join(lines, "\n")
+	if len(payload) > MNBMaxPayloadBytes {
+		return "", fmt.Errorf("payload exceeds %d bytes", MNBMaxPayloadBytes)
+	}
+	return payload, nil
+}
@@ -0,0 +1,39 @@
+package qr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMNBPayloadBuild(t *testing.T) {
+	validUntil := time.Date(2026, 11, 30, 18, 0, 0, 0, time.FixedZone("CET", 3600))
+	payload, err := MNBPayload{
+		TransferType: "HCT",
+		BIC:          "OTPVHUHB",
+		Name:         "Példa Kft.",
+		IBAN:         "HU42117730161111101800000000",
+		AmountCents:  1250000,
+		ValidUntil:   validUntil,
+		Purpose:      "GDDS",
+		Remittance:   "Számla 17",
+		InvoiceID:    "INV-17",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "HCT\n001\n1\nOTPVHUHB\nPélda Kft.\nHU42117730161111101800000000\nHUF12500\n20261130180000+1\nGDDS\nSzámla 17\n\n\nINV-17\n\n\n\n"
+	if payload != want {
+		t.Fatalf("payload=%q\nwant   %q", payload, want)
+	}
+
+	open, err := MNBPayload{TransferType: "HCT", BIC: "OTPVHUHB", Name: "x", IBAN: "HU42117730161111101800000000", OpenAmount: true, ValidUntil: validUntil.UTC()}.Build()
+	if err != nil || open != "HCT\n001\n1\nOTPVHUHB\nx\nHU42117730161111101800000000\n\n20261130170000+0\n\n\n\n\n\n\n\n\n" {
+		t.Fatalf("unexpected open amount payload %q: %v", open, err)
+	}
+	if _, err := (MNBPayload{TransferType: "RTP", BIC: "OTPVHUHB", Name: "x", IBAN: "HU42117730161111101800000000", OpenAmount: true, ValidUntil: validUntil}).Build(); err == nil {
+		t.Fatalf("expected error for RTP without amount")
+	}
+	if _, err := (MNBPayload{TransferType: "HCT", BIC: "OTPVHUHB", Name: "x", IBAN: "HU42117730161111101800000000", AmountCents: 1250, ValidUntil: validUntil}).Build(); err == nil {
+		t.Fatalf("expected error for fractional forints")
+	}
+}
@@ -0,0 +1,190 @@
+package qr
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// PDF417 layout constants (ISO/IEC 15438).
+const (
+	pdf417Start      = 0x1fea8 // 17 modules
+	pdf417Stop       = 0x3fa29 // 18 modules
+	pdf417Pad        = 900
+	pdf417ByteLatch  = 901
+	pdf417ByteLatch6 = 924
+	pdf417MinRows    = 3
+	pdf417MaxRows    = 90
+	pdf417MaxWords   = 928
+	pdf417Modulus    = 929
+
+	// Rendering: each row is three modules high and the symbol keeps a
+	// two-module quiet zone.
+	pdf417RowHeight = 3
+	pdf417Quiet     = 2
+)
+
+// pdf417ByteCompact encodes data in byte compaction mode: groups of six
+// bytes become five base-900 codewords, remaining bytes one codeword each.
+func pdf417ByteCompact(data []byte) []int {
+	words := []int{pdf417ByteLatch}
+	if len(data)%6 == 0 {
+		words[0] = pdf417ByteLatch6
+	}
+	i := 0
+	for ; i+6 <= len(data); i += 6 {
+		var v uint64
+		for _, b := range data[i : i+6] {
+			v = v<<8 | uint64(b)
+		}
+		var group [5]int
+		for j := 4; j >= 0; j-- {
+			group[j] = int(v % 900)
+			v /= 900
+		}
+		words = append(words, group[:]...)
+	}
+	for ; i < len(data); i++ {
+		words = append(words, int(data[i]))
+	}
+	return words
+}
+
+// pdf417Generator returns the coefficients (lowest order first, leading 1
+// omitted) of the error correction generator polynomial prod(x - 3^i),
+// i = 1..2^(level+1), over GF(929).
+func pdf417Generator(level int) []int {
+	k := 1 << (level + 1)
+	g := []int{1}
+	root := 1
+	for i := 0; i < k; i++ {
+		root = root * 3 % pdf417Modulus
+		next := make([]int, len(g)+1)
+		for j, c := range g {
+			next[j+1] = (next[j+1] + c) % pdf417Modulus
+			next[j] = (next[j] + (pdf417Modulus-root)*c) % pdf417Modulus
+		}
+		g = next
+	}
+	return g[:k]
+}
+
+// pdf417ErrorCorrection computes the Reed-Solomon codewords for data.
+func pdf417ErrorCorrection(data []int, level int) []int {
+	a := pdf417Generator(level)
+	k := len(a)
+	ec := make([]int, k)
+	for _, d := range data {
+		t := (d + ec[k-1]) % pdf417Modulus
+		for j := k - 1; j > 0; j-- {
+			ec[j] = (ec[j-1] + pdf417Modulus - t*a[j]%pdf417Modulus) % pdf417Modulus
+		}
+		ec[0] = (pdf417Modulus - t*a[0]%pdf417Modulus) % pdf417Modulus
+	}
+	out := make([]int, k)
+	for j := range ec {
+		if ec[j] != 0 {
+			ec[j] = pdf417Modulus - ec[j]
+		}
+		out[k-1-j] = ec[j]
+	}
+	return out
+}
+
+// EncodePDF417 encodes data in byte compaction mode with the given number
+// of data columns and error correction level (0-8). It returns one row of
+// modules per symbol row, without quiet zone; true is a bar.
+func EncodePDF417(data []byte, columns, level int) ([][]bool, error) {
+	if columns < 1 || columns > 30 {
+		return nil, fmt.Errorf("pdf417: columns must be 1..30")
+	}
+	if level < 0 || level > 8 {
+		return nil, fmt.Errorf("pdf417: error correction level must be 0..8")
+	}
+	words := pdf417ByteCompact(data)
+	ecCount := 1 << (level + 1)
+
+	// Length descriptor + data + error correction, padded to full rows.
+	n := 1 + len(words) + ecCount
+	rows := (n + columns - 1) / columns
+	if rows < pdf417MinRows {
+		rows = pdf417MinRows
+	}
+	if rows > pdf417MaxRows || rows*columns > pdf417MaxWords {
+		return nil, fmt.Errorf("pdf417: data too long")
+	}
+	dataCount := rows*columns - ecCount
+	codewords := make([]int, 0, rows*columns)
+	codewords = append(codewords, dataCount)
+	codewords = append(codewords, words...)
+	for len(codewords) < dataCount {
+		codewords = append(codewords, pdf417Pad)
+	}
+	codewords = append(codewords, pdf417ErrorCorrection(codewords, level)...)
+
+	matrix := make([][]bool, rows)
+	for r := 0; r < rows; r++ {
+		cluster := r % 3
+		base := 30 * (r / 3)
+		var left, right int
+		switch cluster {
+		case 0:
+			left, right = base+(rows-1)/3, base+columns-1
+		case 1:
+			left, right = base+level*3+(rows-1)%3, base+(rows-1)/3
+		default:
+			left, right = base+columns-1, base+level*3+(rows-1)%3
+		}
+
+		row := make([]bool, 0, 17*(columns+4)+1)
+		row = appendPattern(row, pdf417Start, 17)
+		row = appendPattern(row, pdf417Clusters[cluster][left], 17)
+		for _, cw := range codewords[r*columns : (r+1)*columns] {
+			row = appendPattern(row, pdf417Clusters[cluster][cw], 17)
+		}
+		row = appendPattern(row, pdf417Clusters[cluster][right], 17)
+		row = appendPattern(row, pdf417Stop, 18)
+		matrix[r] = row
+	}
+	return matrix, nil
+}
+
+func appendPattern(row []bool, pattern uint32, width int) []bool {
+	for i := width - 1; i >= 0; i-- {
+		row = append(row, pattern>>uint(i)&1 == 1)
+	}
+	return row
+}
+
+// MakePDF417 renders a PDF417 symbol as black bars on a transparent
+// background, scaled to the largest whole module width that fits opt.Size.
+func MakePDF417(payload string, columns, level int, opt Options) ([]byte, error) {
+	matrix, err := EncodePDF417([]byte(payload), columns, level)
+	if err != nil {
+		return nil, err
+	}
+	w := len(matrix[0]) + 2*pdf417Quiet
+	h := len(matrix)*pdf417RowHeight + 2*pdf417Quiet
+	scale := opt.Size / w
+	if scale < 1 {
+		scale = 1
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w*scale, h*scale))
+	black := color.NRGBA{A: 255}
+	for r, row := range matrix {
+		y0 := (pdf417Quiet + r*pdf417RowHeight) * scale
+		for x, bar := range row {
+			if !bar {
+				continue
+			}
+			x0 := (pdf417Quiet + x) * scale
+			for y := y0; y < y0+pdf417RowHeight*scale; y++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetNRGBA(x0+dx, y, black)
+				}
+			}
+		}
+	}
+	return EncodePNG(img)
+}
@@ -0,0 +1,158 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+// readPDF417 maps every row of matrix back to its codewords and returns
+// the data region plus the row indicators.
+func readPDF417(t *testing.T, matrix [][]bool) (words [][]int, left, right []int) {
+	t.Helper()
+	lookup := [3]map[uint32]int{{}, {}, {}}
+	for c := range pdf417Clusters {
+		for v, p := range pdf417Clusters[c] {
+			lookup[c][p] = v
+		}
+	}
+	read := func(row []bool, at int) uint32 {
+		var p uint32
+		for _, b := range row[at : at+17] {
+			p <<= 1
+			if b {
+				p |= 1
+			}
+		}
+		return p
+	}
+	for r, row := range matrix {
+		cols := (len(row)-1)/17 - 4
+		if read(row, 0) != pdf417Start {
+			t.Fatalf("row %d: missing start pattern", r)
+		}
+		var cws []int
+		for i := 1; i <= cols+2; i++ {
+			v, ok := lookup[r%3][read(row, 17*i)]
+			if !ok {
+				t.Fatalf("row %d col %d: pattern not in cluster %d", r, i, r%3*3)
+			}
+			cws = append(cws, v)
+		}
+		left = append(left, cws[0])
+		right = append(right, cws[len(cws)-1])
+		words = append(words, cws[1:len(cws)-1])
+	}
+	return words, left, right
+}
+
+func TestEncodePDF417(t *testing.T) {
+	data := []byte("HRVHUB30\nEUR\n000000000012355\n")
+	const columns, level = 4, 2
+	matrix, err := EncodePDF417(data, columns, level)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	rows, left, right := readPDF417(t, matrix)
+	var cws []int
+	for _, r := range rows {
+		cws = append(cws, r...)
+	}
+
+	// Length descriptor, byte latch and the compacted bytes.
+	ecCount := 1 << (level + 1)
+	if cws[0] != len(cws)-ecCount {
+		t.Fatalf("length descriptor=%d, want %d", cws[0], len(cws)-ecCount)
+	}
+	if want := pdf417ByteCompact(data); !equalInts(cws[1:1+len(want)], want) {
+		t.Fatalf("data codewords=%v, want %v", cws[1:1+len(want)], want)
+	}
+
+	// Every syndrome c(3^i), i = 1..ecCount, vanishes for a valid symbol.
+	root := 1
+	for i := 1; i <= ecCount; i++ {
+		root = root * 3 % pdf417Modulus
+		s := 0
+		for _, c := range cws {
+			s = (s*root + c) % pdf417Modulus
+		}
+		if s != 0 {
+			t.Fatalf("syndrome %d = %d", i, s)
+		}
+	}
+
+	// Row indicators carry rows, columns and level.
+	n := len(matrix)
+	for r := range matrix {
+		base := 30 * (r / 3)
+		wantLeft := []int{base + (n-1)/3, base + level*3 + (n-1)%3, base + columns - 1}[r%3]
+		if left[r] != wantLeft {
+			t.Fatalf("row %d left indicator=%d, want %d", r, left[r], wantLeft)
+		}
+		wantRight := []int{base + columns - 1, base + (n-1)/3, base + level*3 + (n-1)%3}[r%3]
+		if right[r] != wantRight {
+			t.Fatalf("row %d right indicator=%d, want %d", r, right[r], wantRight)
+		}
+	}
+}
+
+func TestPDF417ByteCompact(t *testing.T) {
+	// Six bytes latch with 924 and pack into five base-900 digits.
+	if got := pdf417ByteCompact([]byte{0, 0, 0, 0, 1, 0}); !equalInts(got, []int{924, 0, 0, 0, 0, 256}) {
+		t.Fatalf("unexpected compaction: %v", got)
+	}
+	if got := pdf417ByteCompact([]byte("ab")); !equalInts(got, []int{901, 'a', 'b'}) {
+		t.Fatalf("unexpected compaction: %v", got)
+	}
+}
+
+func TestPDF417Clusters(t *testing.T) {
+	for c := range pdf417Clusters {
+		for v, p := range pdf417Clusters[c] {
+			var runs []int
+			prev, n := true, 0
+			for i := 16; i >= 0; i-- {
+				b := p>>uint(i)&1 == 1
+				if b != prev {
+					runs = append(runs, n)
+					prev, n = b, 0
+				}
+				n++
+			}
+			runs = append(runs, n)
+			if len(runs) != 8 || (runs[0]-runs[2]+runs[4]-runs[6]+9)%9 != c*3 {
+				t.Fatalf("cluster %d codeword %d: bad pattern %05x", c*3, v, p)
+			}
+		}
+	}
+}
+
+func TestMakePDF417(t *testing.T) {
+	pngBytes, err := MakePDF417("HRVHUB30\n", 9, 4, Options{Size: 512})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(pngBytes))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	// 9 columns: 17*(9+4)+1 modules plus the quiet zone, scaled by 2.
+	if w := img.Bounds().Dx(); w != (17*13+1+2*pdf417Quiet)*2 {
+		t.Fatalf("width=%d", w)
+	}
+	if _, err := MakePDF417(string(make([]byte, 2000)), 9, 4, Options{Size: 512}); err == nil {
+		t.Fatalf("expected error for oversized data")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -7,6 +7,8 @@ import (
 type Options struct {
 	Size int
 	ECC  qrcode.RecoveryLevel
+	// Version forces the QR version (1-40); 0 picks the smallest that fits.
+	Version int
 }
 
 type Style struct {
@@ -16,6 +18,15 @@ type Style struct {
 	QuietZone    int
 }
 
+// Margin returns the quiet zone in modules MakeQRStyled leaves around the
+// symbol: QuietZone (default 4) on top of the encoder's 4-module border.
+func (s Style) Margin() int {
+	if s.QuietZone > 0 {
+		return 4 + s.QuietZone
+	}
+	return 8
+}
+
 func DefaultPublicOptions() Options {
 	// Public mode: fixed, boring, highly compatible.
 	return Options{
@@ -36,8 +47,15 @@ func DefaultAuthOptions(withLogo bool) Options {
 	}
 }
 
+func newQRCode(payload string, opt Options) (*qrcode.QRCode, error) {
+	if opt.Version > 0 {
+		return qrcode.NewWithForcedVersion(payload, opt.Version, opt.ECC)
+	}
+	return qrcode.New(payload, opt.ECC)
+}
+
 func MakeQR(payload string, opt Options) ([]byte, error) {
-	qr, err := qrcode.New(payload, opt.ECC)
+	qr, err := newQRCode(payload, opt)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +67,7 @@ func MakeQR(payload string, opt Options) ([]byte, error) {
 }
 
 func MakeQRStyled(payload string, opt Options, style Style) ([]byte, error) {
-	qr, err := qrcode.New(payload, opt.ECC)
+	qr, err := newQRCode(payload, opt)
 	if err != nil {
 		return nil, err
 	}
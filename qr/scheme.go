@@ -0,0 +1,223 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+// BuildPayload builds the QR payload for validated input of any supported
+// scheme. Server and CLI both go through it.
+func BuildPayload(c *validate.Clean) (string, error) {
+	switch c.Scheme {
+	case validate.SchemeEPCSCT:
+		return EPCPayload{
+			Version:        c.Version,
+			Charset:        c.Charset,
+			BIC:            c.BIC,
+			Name:           c.Name,
+			IBAN:           c.IBAN,
+			AmountCents:    c.AmountCents,
+			OpenAmount:     c.OpenAmount,
+			Purpose:        c.Purpose,
+			RemittanceRef:  c.RemittanceReference,
+			RemittanceText: c.RemittanceText,
+			Information:    c.Information,
+		}.Build()
+	case validate.SchemeSwissQR:
+		return SwissQRPayload{
+			IBAN:            c.IBAN,
+			Creditor:        swissAddress(c.Creditor),
+			AmountCents:     c.AmountCents,
+			OpenAmount:      c.OpenAmount,
+			Currency:        c.Currency,
+			Debtor:          swissAddress(c.Debtor),
+			ReferenceType:   c.ReferenceType,
+			Reference:       c.RemittanceReference,
+			Message:         c.RemittanceText,
+			BillInformation: c.Information,
+		}.Build()
+	case validate.SchemeCZSPD:
+		return SPDPayload{
+			IBAN:           c.IBAN,
+			BIC:            c.BIC,
+			AmountCents:    c.AmountCents,
+			OpenAmount:     c.OpenAmount,
+			Currency:       c.Currency,
+			Reference:      c.RemittanceReference,
+			Name:           c.Name,
+			DueDate:        c.DueDate,
+			Message:        c.RemittanceText,
+			VariableSymbol: c.VariableSymbol,
+			SpecificSymbol: c.SpecificSymbol,
+			ConstantSymbol: c.ConstantSymbol,
+		}.Build()
+	case validate.SchemeSKPayBySquare:
+		return PayBySquarePayload{
+			IBAN:            c.IBAN,
+			BIC:             c.BIC,
+			AmountCents:     c.AmountCents,
+			OpenAmount:      c.OpenAmount,
+			Currency:        c.Currency,
+			DueDate:         c.DueDate,
+			VariableSymbol:  c.VariableSymbol,
+			ConstantSymbol:  c.ConstantSymbol,
+			SpecificSymbol:  c.SpecificSymbol,
+			Reference:       c.RemittanceReference,
+			Note:            c.RemittanceText,
+			BeneficiaryName: c.Name,
+			AddressLine1:    joinNonEmpty(c.Creditor.Street, c.Creditor.BuildingNumber),
+			AddressLine2:    joinNonEmpty(c.Creditor.PostalCode, c.Creditor.Town),
+		}.Build()
+	case validate.SchemeSIUPN:
+		return UPNPayload{
+			PayerName:   c.Debtor.Name,
+			PayerStreet: c.Debtor.Street,
+			PayerTown:   c.Debtor.Town,
+			AmountCents: c.AmountCents,
+			PurposeCode: c.Purpose,
+			Purpose:     c.RemittanceText,
+			DueDate:     c.DueDate,
+			IBAN:        c.IBAN,
+			Reference:   c.RemittanceReference,
+			Name:        c.Creditor.Name,
+			Street:      c.Creditor.Street,
+			Town:        c.Creditor.Town,
+		}.Build()
+	case validate.SchemeHUMNB:
+		validUntil, err := time.Parse(time.RFC3339, c.ValidUntil)
+		if err != nil {
+			return "", fmt.Errorf("invalid validity timestamp")
+		}
+		return MNBPayload{
+			TransferType: c.TransferType,
+			BIC:          c.BIC,
+			Name:         c.Name,
+			IBAN:         c.IBAN,
+			AmountCents:  c.AmountCents,
+			OpenAmount:   c.OpenAmount,
+			ValidUntil:   validUntil,
+			Purpose:      c.Purpose,
+			Remittance:   c.RemittanceText,
+			InvoiceID:    c.RemittanceReference,
+		}.Build()
+	case validate.SchemeHRHUB3:
+		return HUB3Payload{
+			Currency:    c.Currency,
+			AmountCents: c.AmountCents,
+			PayerName:   c.Debtor.Name,
+			PayerStreet: c.Debtor.Street,
+			PayerTown:   c.Debtor.Town,
+			Name:        c.Creditor.Name,
+			Street:      c.Creditor.Street,
+			Town:        c.Creditor.Town,
+			IBAN:        c.IBAN,
+			Reference:   c.RemittanceReference,
+			PurposeCode: c.Purpose,
+			Description: c.RemittanceText,
+		}.Build()
+	case validate.SchemeFIBarcode:
+		return FIBarcodePayload{
+			IBAN:        c.IBAN,
+			AmountCents: c.AmountCents,
+			Reference:   c.RemittanceReference,
+			DueDate:     c.DueDate,
+		}.Build()
+	case validate.SchemeBezahlCode:
+		reason := c.RemittanceText
+		if c.RemittanceReference != "" {
+			reason = c.RemittanceReference
+		}
+		return BezahlCodePayload{
+			Name:          c.Name,
+			IBAN:          c.IBAN,
+			BIC:           c.BIC,
+			AmountCents:   c.AmountCents,
+			OpenAmount:    c.OpenAmount,
+			Reason:        reason,
+			ExecutionDate: c.ExecutionDate,
+			Period:        c.Period,
+		}.Build()
+	default:
+		return "", fmt.Errorf("unsupported scheme")
+	}
+}
+
+// SchemeIsQR reports whether a scheme renders as a QR code. Other schemes
+// render through MakeBarcode; QR module styles do not apply to them, but
+// palettes do.
+func SchemeIsQR(scheme string) bool {
+	return scheme != validate.SchemeHRHUB3 && scheme != validate.SchemeFIBarcode
+}
+
+// MakeBarcode renders the symbol of a scheme that does not use QR codes.
+func MakeBarcode(scheme, payload string, opt Options) ([]byte, error) {
+	switch scheme {
+	case validate.SchemeHRHUB3:
+		return MakePDF417(payload, HUB3Columns, HUB3ECLevel, opt)
+	case validate.SchemeFIBarcode:
+		return MakeCode128(payload, opt)
+	default:
+		return nil, fmt.Errorf("scheme %s renders as a QR code", scheme)
+	}
+}
+
+// SchemeAllowsLogo reports whether a custom logo may cover the centre of
+// the code. Schemes with a mandatory centre mark, a fixed ECC level or a
+// non-QR symbology do not allow it.
+func SchemeAllowsLogo(scheme string) bool {
+	return SchemeIsQR(scheme) && scheme != validate.SchemeSwissQR && scheme != validate.SchemeSIUPN
+}
+
+// SchemeOptions applies the symbol version and ECC level a scheme
+// mandates, such as version 15-M for UPN QR.
+func SchemeOptions(scheme string, opt Options) Options {
+	if scheme == validate.SchemeSIUPN {
+		opt.Version = UPNQRVersion
+		opt.ECC = UPNQRECC
+	}
+	return opt
+}
+
+// ApplySchemeMarks draws the marks a scheme requires on top of a rendered
+// QR code, such as the Swiss cross. quietZone is the margin in modules the
+// code was rendered with (Style.Margin for MakeQRStyled; 0 means the 4 of
+// MakeQR). Other schemes are returned unchanged.
+func ApplySchemeMarks(scheme, payload string, opt Options, quietZone int, qrPNG []byte) ([]byte, error) {
+	if scheme != validate.SchemeSwissQR {
+		return qrPNG, nil
+	}
+	q, err := newQRCode(payload, opt)
+	if err != nil {
+		return nil, err
+	}
+	// The bitmap always includes a 4-module border on each side.
+	n := len(q.Bitmap()) - 8
+	if quietZone <= 0 {
+		quietZone = 4
+	}
+	return OverlaySwissCross(qrPNG, float64(n)/float64(n+2*quietZone))
+}
+
+func swissAddress(a validate.Address) SwissAddress {
+	return SwissAddress{
+		Name:           a.Name,
+		Street:         a.Street,
+		BuildingNumber: a.BuildingNumber,
+		PostalCode:     a.PostalCode,
+		Town:           a.Town,
+		Country:        a.Country,
+	}
+}
+
+func joinNonEmpty(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,76 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SPDPayload holds the fields of a Czech Short Payment Descriptor (SPD 1.0).
+// DueDate is YYYY-MM-DD; empty fields are omitted from the payload.
+type SPDPayload struct {
+	IBAN           string
+	BIC            string
+	AmountCents    int64
+	OpenAmount     bool
+	Currency       string
+	Reference      string
+	Name           string
+	DueDate        string
+	Message        string
+	VariableSymbol string
+	SpecificSymbol string
+	ConstantSymbol string
+}
+
+func (p SPDPayload) Build() (string, error) {
+	if p.IBAN == "" {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.OpenAmount {
+		if p.AmountCents != 0 {
+			return "", fmt.Errorf("open amount payload must not carry an amount")
+		}
+	} else if p.AmountCents <= 0 {
+		return "", fmt.Errorf("missing required fields")
+	}
+
+	acc := p.IBAN
+	if p.BIC != "" {
+		acc += "+" + p.BIC
+	}
+	fields := []struct{ key, value string }{
+		{"ACC", acc},
+		{"AM", ""},
+		{"CC", p.Currency},
+		{"RF", p.Reference},
+		{"RN", p.Name},
+		{"DT", strings.ReplaceAll(p.DueDate, "-", "")},
+		{"MSG", p.Message},
+		{"X-VS", p.VariableSymbol},
+		{"X-SS", p.SpecificSymbol},
+		{"X-KS", p.ConstantSymbol},
+	}
+	if !p.OpenAmount {
+		fields[1].value = fmt.Sprintf("%d.%02d", p.AmountCents/100, p.AmountCents%100)
+	}
+
+	var b strings.Builder
+	b.WriteString("SPD*1.0")
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		b.WriteString("*")
+		b.WriteString(f.key)
+		b.WriteString(":")
+		b.WriteString(spdEscape(f.value))
+	}
+	return b.String(), nil
+}
+
+// spdEscape percent-encodes the field separator, which values must not
+// contain, and the percent sign itself.
+func spdEscape(v string) string {
+	v = strings.ReplaceAll(v, "%", "%25")
+	return strings.ReplaceAll(v, "*", "%2A")
+}
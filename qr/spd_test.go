@@ -0,0 +1,32 @@
+package qr
+
+import "testing"
+
+func TestSPDPayloadBuild(t *testing.T) {
+	payload, err := SPDPayload{
+		IBAN:           "CZ6508000000192000145399",
+		BIC:            "GIBACZPX",
+		AmountCents:    45000,
+		Currency:       "CZK",
+		Name:           "Example s.r.o.",
+		DueDate:        "2026-11-30",
+		Message:        "Faktura *17* 100%",
+		VariableSymbol: "1234567890",
+		ConstantSymbol: "0308",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "SPD*1.0*ACC:CZ6508000000192000145399+GIBACZPX*AM:450.00*CC:CZK*RN:Example s.r.o.*DT:20261130*MSG:Faktura %2A17%2A 100%25*X-VS:1234567890*X-KS:0308"
+	if payload != want {
+		t.Fatalf("payload=%q\nwant   %q", payload, want)
+	}
+
+	payload, err = SPDPayload{IBAN: "CZ6508000000192000145399", Currency: "CZK", OpenAmount: true}.Build()
+	if err != nil || payload != "SPD*1.0*ACC:CZ6508000000192000145399*CC:CZK" {
+		t.Fatalf("unexpected open amount payload %q: %v", payload, err)
+	}
+	if _, err := (SPDPayload{IBAN: "CZ6508000000192000145399", Currency: "CZK"}).Build(); err == nil {
+		t.Fatalf("expected error for missing amount")
+	}
+}
@@ -0,0 +1,142 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// SwissQRMaxPayloadChars is the QR-bill limit for the whole payload.
+const SwissQRMaxPayloadChars = 997
+
+// SwissAddress is a structured (type S) QR-bill address.
+type SwissAddress struct {
+	Name           string
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	Country        string
+}
+
+// SwissQRPayload holds the fields of a Swiss QR-bill (SPC 0200) payload.
+// A zero Debtor leaves the ultimate debtor block empty; OpenAmount leaves
+// the amount empty for the payer to fill in.
+type SwissQRPayload struct {
+	IBAN            string
+	Creditor        SwissAddress
+	AmountCents     int64
+	OpenAmount      bool
+	Currency        string
+	Debtor          SwissAddress
+	ReferenceType   string
+	Reference       string
+	Message         string
+	BillInformation string
+}
+
+func (p SwissQRPayload) Build() (string, error) {
+	if p.IBAN == "" || p.Creditor.Name == "" || p.Creditor.PostalCode == "" || p.Creditor.Town == "" || p.Creditor.Country == "" {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.Currency != "CHF" && p.Currency != "EUR" {
+		return "", fmt.Errorf("unsupported currency")
+	}
+	switch p.ReferenceType {
+	case "QRR", "SCOR":
+		if p.Reference == "" {
+			return "", fmt.Errorf("missing reference")
+		}
+	case "NON":
+		if p.Reference != "" {
+			return "", fmt.Errorf("reference type NON must not carry a reference")
+		}
+	default:
+		return "", fmt.Errorf("unsupported reference type")
+	}
+
+	amount := ""
+	if p.OpenAmount {
+		if p.AmountCents != 0 {
+			return "", fmt.Errorf("open amount payload must not carry an amount")
+		}
+	} else {
+		if p.AmountCents <= 0 {
+			return "", fmt.Errorf("missing required fields")
+		}
+		amount = fmt.Sprintf("%d.%02d", p.AmountCents/100, p.AmountCents%100)
+	}
+
+	lines := []string{"SPC", "0200", "1", p.IBAN}
+	lines = append(lines, swissAddressLines(p.Creditor)...)
+	// Ultimate creditor: reserved for future use, always empty.
+	lines = append(lines, "", "", "", "", "", "", "")
+	lines = append(lines, amount, p.Currency)
+	lines = append(lines, swissAddressLines(p.Debtor)...)
+	lines = append(lines, p.ReferenceType, p.Reference, p.Message, "EPD")
+	if p.BillInformation != "" {
+		lines = append(lines, p.BillInformation)
+	}
+
+	payload := strings.Join(lines, "\n")
+	if utf8.RuneCountInString(payload) > SwissQRMaxPayloadChars {
+		return "", fmt.Errorf("payload exceeds %d characters", SwissQRMaxPayloadChars)
+	}
+	return payload, nil
+}
+
+func swissAddressLines(a SwissAddress) []string {
+	if a == (SwissAddress{}) {
+		return []string{"", "", "", "", "", "", ""}
+	}
+	return []string{"S", a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, a.Country}
+}
+
+// OverlaySwissCross draws the Swiss cross the QR-bill standard requires in
+// the centre of the code: 7 mm on a 46 mm symbol. symbolRatio is the share
+// of the image width taken by the symbol without its quiet zone.
+func OverlaySwissCross(qrPNG []byte, symbolRatio float64) ([]byte, error) {
+	qrImg, err := png.Decode(bytes.NewReader(qrPNG))
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(qrImg.Bounds())
+	draw.Draw(img, img.Bounds(), qrImg, image.Point{}, draw.Src)
+
+	w := img.Bounds().Dx()
+	side := int(math.Round(float64(w) * symbolRatio * 7 / 46))
+	if side < 14 {
+		side = 14
+	}
+	x := (w - side) / 2
+	y := (img.Bounds().Dy() - side) / 2
+	black := color.RGBA{A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// White margin, black square, then the white cross with the flag's
+	// proportions: arms 6/32 wide and 20/32 long.
+	fillRect(img, x, y, side, side, white)
+	border := side / 14
+	inner := side - 2*border
+	fillRect(img, x+border, y+border, inner, inner, black)
+	arm := int(math.Round(float64(inner) * 6 / 32))
+	span := int(math.Round(float64(inner) * 20 / 32))
+	cx := x + border + (inner-arm)/2
+	cy := y + border + (inner-arm)/2
+	sx := x + border + (inner-span)/2
+	sy := y + border + (inner-span)/2
+	fillRect(img, cx, sy, arm, span, white)
+	fillRect(img, sx, cy, span, arm, white)
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, img); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
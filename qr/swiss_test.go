@@ -0,0 +1,157 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSwissQRPayloadBuild(t *testing.T) {
+	payload, err := SwissQRPayload{
+		IBAN: "CH4431999123000889012",
+		Creditor: SwissAddress{
+			Name:           "Robert Schneider AG",
+			Street:         "Rue du Lac",
+			BuildingNumber: "1268",
+			PostalCode:     "2501",
+			Town:           "Biel",
+			Country:        "CH",
+		},
+		AmountCents:     194975,
+		Currency:        "CHF",
+		ReferenceType:   "QRR",
+		Reference:       "210000000003139471430009017",
+		Message:         "Order of 15 June 2020",
+		BillInformation: "//S1/10/10201409/11/200701/20/140.000-53",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(payload, "\n")
+	if len(lines) != 32 {
+		t.Fatalf("expected 32 lines, got %d: %q", len(lines), payload)
+	}
+	want := map[int]string{
+		0: "SPC", 1: "0200", 2: "1", 3: "CH4431999123000889012",
+		4: "S", 5: "Robert Schneider AG", 10: "CH",
+		11: "", 17: "",
+		18: "1949.75", 19: "CHF",
+		20: "", 26: "",
+		27: "QRR", 28: "210000000003139471430009017",
+		29: "Order of 15 June 2020", 30: "EPD",
+		31: "//S1/10/10201409/11/200701/20/140.000-53",
+	}
+	for i, v := range want {
+		if lines[i] != v {
+			t.Fatalf("line %d=%q want %q", i+1, lines[i], v)
+		}
+	}
+}
+
+func TestSwissQRPayloadBuild_Errors(t *testing.T) {
+	base := SwissQRPayload{
+		IBAN:          "CH9300762011623852957",
+		Creditor:      SwissAddress{Name: "Example AG", PostalCode: "8000", Town: "Zürich", Country: "CH"},
+		Currency:      "CHF",
+		OpenAmount:    true,
+		ReferenceType: "NON",
+	}
+	payload, err := base.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(payload, "\n"); len(lines) != 31 || lines[18] != "" || lines[30] != "EPD" {
+		t.Fatalf("unexpected open amount payload: %q", payload)
+	}
+
+	bad := base
+	bad.Reference = "RF18539007547034"
+	if _, err := bad.Build(); err == nil {
+		t.Fatalf("expected error for NON with a reference")
+	}
+	bad = base
+	bad.Currency = "USD"
+	if _, err := bad.Build(); err == nil {
+		t.Fatalf("expected error for USD")
+	}
+	bad = base
+	bad.Creditor.Town = ""
+	if _, err := bad.Build(); err == nil {
+		t.Fatalf("expected error for missing town")
+	}
+}
+
+func TestApplySchemeMarks_SwissCross(t *testing.T) {
+	payload := "SPC\n0200\n1\nCH9300762011623852957"
+	opt := DefaultPublicOptions()
+	pngBytes, err := MakeQR(payload, opt)
+	if err != nil {
+		t.Fatalf("make qr: %v", err)
+	}
+	marked, err := ApplySchemeMarks("swiss_qr", payload, opt, 0, pngBytes)
+	if err != nil {
+		t.Fatalf("apply marks: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(marked))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	b := img.Bounds()
+	cx, cy := b.Dx()/2, b.Dy()/2
+	if r, g, bl, a := img.At(cx, cy).RGBA(); r != 0xffff || g != 0xffff || bl != 0xffff || a != 0xffff {
+		t.Fatalf("expected white cross in the centre")
+	}
+	// Just inside the black square, off the cross arms.
+	off := b.Dx() * 7 / 46 / 4
+	if r, g, bl, a := img.At(cx-off, cy-off).RGBA(); r != 0 || g != 0 || bl != 0 || a != 0xffff {
+		t.Fatalf("expected black square around the cross")
+	}
+
+	same, err := ApplySchemeMarks("epc_sct", payload, opt, 0, pngBytes)
+	if err != nil || !bytes.Equal(same, pngBytes) {
+		t.Fatalf("epc_sct should not be marked")
+	}
+}
+
+// TestApplySchemeMarks_QuietZone checks that the cross stays 7/46 of the
+// symbol (not of the image) when a key renders with a wider quiet zone.
+func TestApplySchemeMarks_QuietZone(t *testing.T) {
+	payload := "SPC\n0200\n1\nCH9300762011623852957"
+	opt := DefaultPublicOptions()
+	for _, quiet := range []int{0, 2, 12} {
+		style := Style{QuietZone: quiet}
+		pngBytes, err := MakeQRStyled(payload, opt, style)
+		if err != nil {
+			t.Fatalf("make qr: %v", err)
+		}
+		marked, err := ApplySchemeMarks("swiss_qr", payload, opt, style.Margin(), pngBytes)
+		if err != nil {
+			t.Fatalf("apply marks: %v", err)
+		}
+		img, err := png.Decode(bytes.NewReader(marked))
+		if err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		b := img.Bounds()
+		// The symbol starts at the first dark pixel; the cross margin is
+		// the only opaque white in the image.
+		left, crossLeft := b.Dx(), b.Dx()
+		for y := 0; y < b.Dy(); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				r, _, _, a := img.At(x, y).RGBA()
+				if a == 0xffff && r == 0 && x < left {
+					left = x
+				}
+				if a == 0xffff && r == 0xffff && x < crossLeft {
+					crossLeft = x
+				}
+			}
+		}
+		symbol := float64(b.Dx() - 2*left)
+		side := float64(b.Dx() - 2*crossLeft)
+		if want := symbol * 7 / 46; side < want-3 || side > want+3 {
+			t.Fatalf("quiet zone %d: cross %.0fpx, want %.0fpx for a %.0fpx symbol", quiet, side, want, symbol)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// UPN QR symbols are always version 15 with ECC level M; the payload is
+// padded with spaces to a fixed length that fills the symbol.
+const (
+	UPNQRVersion     = 15
+	UPNQRECC         = qrcode.Medium
+	UPNQRPayloadSize = 411
+)
+
+// UPNPayload holds the fields of a Slovenian UPN QR payload. Address lines
+// are free text; DueDate is YYYY-MM-DD.
+type UPNPayload struct {
+	PayerName   string
+	PayerStreet string
+	PayerTown   string
+	AmountCents int64
+	PurposeCode string
+	Purpose     string
+	DueDate     string
+	IBAN        string
+	Reference   string
+	Name        string
+	Street      string
+	Town        string
+}
+
+// Build returns the ISO-8859-2 payload: 19 LF-terminated fields, the
+// control sum (their length in bytes, three digits) and space padding.
+func (p UPNPayload) Build() (string, error) {
+	if p.IBAN == "" || p.Reference == "" || p.Name == "" || p.Street == "" || p.Town == "" || p.Purpose == "" || len(p.PurposeCode) != 4 {
+		return "", fmt.Errorf("missing required fields")
+	}
+	if p.AmountCents <= 0 || p.AmountCents > 99999999999 {
+		return "", fmt.Errorf("invalid amount")
+	}
+
+	dueDate := ""
+	if d := p.DueDate; d != "" {
+		if len(d) != 10 {
+			return "", fmt.Errorf("invalid due date")
+		}
+		dueDate = d[8:10] + "." + d[5:7] + "." + d[:4]
+	}
+
+	fields := []struct {
+		value string
+		max   int
+	}{
+		{"UPNQR", 5},
+		{"", 34}, // payer IBAN
+		{"", 1},  // deposit
+		{"", 1},  // withdrawal
+		{"", 26}, // payer reference
+		{p.PayerName, 33},
+		{p.PayerStreet, 33},
+		{p.PayerTown, 33},
+		{fmt.Sprintf("%011d", p.AmountCents), 11},
+		{"", 10}, // payment date
+		{"", 1},  // urgent
+		{p.PurposeCode, 4},
+		{p.Purpose, 42},
+		{dueDate, 10},
+		{p.IBAN, 34},
+		{p.Reference, 26},
+		{p.Name, 33},
+		{p.Street, 33},
+		{p.Town, 33},
+	}
+
+	var b strings.Builder
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return "", fmt.Errorf("field exceeds %d characters: %q", f.max, f.value)
+		}
+		if strings.ContainsAny(f.value, "\r\n") {
+			return "", fmt.Errorf("fields must not contain line breaks")
+		}
+		b.WriteString(f.value)
+		b.WriteString("\n")
+	}
+	encoded, err := charset.Encode(b.String(), charset.ISO8859_2)
+	if err != nil {
+		return "", err
+	}
+	payload := encoded + fmt.Sprintf("%03d", len(encoded)) + "\n"
+	if len(payload) > UPNQRPayloadSize {
+		return "", fmt.Errorf("payload exceeds %d bytes", UPNQRPayloadSize)
+	}
+	return payload + strings.Repeat(" ", UPNQRPayloadSize-len(payload)), nil
+}
@@ -0,0 +1,64 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/charset"
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func TestUPNPayloadBuild(t *testing.T) {
+	payload, err := UPNPayload{
+		PayerName:   "Janez Novak",
+		PayerTown:   "2000 Maribor",
+		AmountCents: 12550,
+		PurposeCode: "OTHR",
+		Purpose:     "Plačilo računa 17",
+		DueDate:     "2026-11-30",
+		IBAN:        "SI56191000000123438",
+		Reference:   "SI121234567890120",
+		Name:        "Podjetje d.o.o.",
+		Street:      "Slovenska cesta 1",
+		Town:        "1000 Ljubljana",
+	}.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(payload) != UPNQRPayloadSize {
+		t.Fatalf("payload length=%d, want %d", len(payload), UPNQRPayloadSize)
+	}
+	text, err := charset.Decode(payload, charset.ISO8859_2)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	body := "UPNQR\n\n\n\n\nJanez Novak\n\n2000 Maribor\n00000012550\n\n\nOTHR\nPlačilo računa 17\n30.11.2026\n" +
+		"SI56191000000123438\nSI121234567890120\nPodjetje d.o.o.\nSlovenska cesta 1\n1000 Ljubljana\n"
+	// The control sum counts bytes; č is a single byte in ISO-8859-2.
+	want := body + "171\n"
+	if got := strings.TrimRight(text, " "); got != want {
+		t.Fatalf("payload=%q\nwant   %q", got, want)
+	}
+
+	if _, err := (UPNPayload{Name: "x"}).Build(); err == nil {
+		t.Fatalf("expected error for missing fields")
+	}
+}
+
+func TestSchemeOptionsUPN(t *testing.T) {
+	opt := SchemeOptions(validate.SchemeSIUPN, DefaultAuthOptions(false))
+	if opt.Version != UPNQRVersion || opt.ECC != UPNQRECC {
+		t.Fatalf("unexpected options: %+v", opt)
+	}
+	q, err := newQRCode(strings.Repeat(" ", UPNQRPayloadSize), opt)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// Version 15 is 77 modules plus the 4-module quiet zone on each side.
+	if n := len(q.Bitmap()); n != 85 {
+		t.Fatalf("bitmap size=%d, want 85", n)
+	}
+	if SchemeOptions(validate.SchemeEPCSCT, opt).Version != UPNQRVersion {
+		t.Fatalf("other schemes must keep their options")
+	}
+}
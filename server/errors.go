@@ -5,6 +5,8 @@ type ErrorCode string
 const (
 	CodeInvalidJSON        ErrorCode = "invalid_json"
 	CodeInvalidInput       ErrorCode = "invalid_input"
+	CodePayloadTooLarge    ErrorCode = "payload_too_large"
+	CodeNonSEPAIBAN        ErrorCode = "non_sepa_iban"
 	CodeUnauthorized       ErrorCode = "unauthorized"
 	CodeRateLimited        ErrorCode = "rate_limited"
 	CodeMethodNotAllowed   ErrorCode = "method_not_allowed"
@@ -14,7 +16,7 @@ const (
 
 func errorStatus(code ErrorCode) int {
 	switch code {
-	case CodeInvalidJSON, CodeInvalidInput:
+	case CodeInvalidJSON, CodeInvalidInput, CodePayloadTooLarge, CodeNonSEPAIBAN:
 		return 400
 	case CodeUnauthorized:
 		return 401
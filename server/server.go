@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
@@ -16,8 +17,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/safe-cap/sepaqx/auth"
 	"github.com/safe-cap/sepaqx/config"
@@ -54,6 +59,7 @@ func New(cfg *config.Config, keyStore *keys.Store, defaultErrorPNG []byte) *Serv
 	mux.HandleFunc("/version", s.handleVersion)
 	mux.HandleFunc("/sepa-qr", s.handleSEPA)
 	mux.HandleFunc("/sepa-qr/validate", s.handleValidate)
+	mux.HandleFunc("/sepa-qr/purpose-codes", s.handlePurposeCodes)
 
 	handler := http.Handler(mux)
 	if cfg.AccessLog {
@@ -247,31 +253,25 @@ func (s *Server) handleSEPA(w http.ResponseWriter, r *http.Request) {
 		parsedIn, parseErr := inputFromQuery(q)
 		if parseErr != nil {
 			s.logLimiter.Logf(string(CodeInvalidInput), "invalid input: %v", parseErr)
-			field := fieldFromValidationError(parseErr.Error())
+			field := fieldFromValidationError(parseErr)
 			s.writeError(w, r, CodeInvalidInput, parseErr.Error(), field)
 			return
 		}
 		in = parsedIn
 	}
 
+	applyKeyDefaults(&in, keyCfg)
+
 	cleaned, err := validate.CleanAndValidate(in)
 	if err != nil {
-		s.logLimiter.Logf(string(CodeInvalidInput), "invalid input: %v", err)
-		field := fieldFromValidationError(err.Error())
-		s.writeError(w, r, CodeInvalidInput, err.Error(), field)
+		code := validationErrorCode(err)
+		s.logLimiter.Logf(string(code), "invalid input: %v", err)
+		field := fieldFromValidationError(err)
+		s.writeError(w, r, code, err.Error(), field)
 		return
 	}
 
-	payload, err := qr.BuildEPCPayload(
-		cleaned.Name,
-		cleaned.IBAN,
-		cleaned.BIC,
-		cleaned.AmountCents,
-		cleaned.Purpose,
-		cleaned.RemittanceReference,
-		cleaned.RemittanceText,
-		cleaned.Information,
-	)
+	payload, err := qr.BuildPayload(cleaned)
 	if err != nil {
 		s.writeError(w, r, CodePayloadBuildFailed, "payload build failed", "")
 		return
@@ -280,7 +280,9 @@ func (s *Server) handleSEPA(w http.ResponseWriter, r *http.Request) {
 	// QR generation options:
 	// - Public: size from global QR_SIZE, ECC=M, margin=4 (library default), no logo, black on transparent.
 	// - Auth:  size from global QR_SIZE (or per-key qr_size override), ECC=M unless logo is used (then ECC=H), palette/logo only via key.
-	withLogo := !isPublic && keyCfg.LogoPath != ""
+	// - Schemes with a mandated symbol (si_upn: version 15, ECC=M) override version and ECC.
+	// - Non-QR schemes (hr_hub3: PDF417, fi_barcode: Code 128) ignore module styles and logos; palettes still apply.
+	withLogo := !isPublic && keyCfg.LogoPath != "" && qr.SchemeAllowsLogo(cleaned.Scheme)
 	opt := qr.DefaultPublicOptions()
 	opt.Size = s.cfg.QRSize
 	if !isPublic {
@@ -290,23 +292,29 @@ func (s *Server) handleSEPA(w http.ResponseWriter, r *http.Request) {
 			opt.Size = keyCfg.QRSize
 		}
 	}
+	opt = qr.SchemeOptions(cleaned.Scheme, opt)
 
 	cacheKey := buildCacheKey(isPublic, cleaned, keyCfg, s.cfg.LogoMaxRatio, opt)
 	if cached, ok := s.pngCache.Get(cacheKey); ok {
-		s.writePNG(w, r, cached)
+		s.writePNG(w, r, cached, cleaned.Warnings)
 		return
 	}
 
 	var pngBytes []byte
-	if !isPublic && (keyCfg.ModuleStyle == "rounded" || keyCfg.ModuleStyle == "blob" || keyCfg.CornerRadius > 0 || keyCfg.QuietZone > 0) {
+	quietZone := 0
+	switch {
+	case !qr.SchemeIsQR(cleaned.Scheme):
+		pngBytes, err = qr.MakeBarcode(cleaned.Scheme, payload, opt)
+	case !isPublic && (keyCfg.ModuleStyle == "rounded" || keyCfg.ModuleStyle == "blob" || keyCfg.CornerRadius > 0 || keyCfg.QuietZone > 0):
 		style := qr.Style{
 			CornerRadius: keyCfg.CornerRadius,
 			ModuleStyle:  keyCfg.ModuleStyle,
 			ModuleRadius: keyCfg.ModuleRadius,
 			QuietZone:    keyCfg.QuietZone,
 		}
+		quietZone = style.Margin()
 		pngBytes, err = qr.MakeQRStyled(payload, opt, style)
-	} else {
+	default:
 		pngBytes, err = qr.MakeQR(payload, opt)
 	}
 	if err != nil {
@@ -348,7 +356,7 @@ func (s *Server) handleSEPA(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Overlay logo (auth only). ECC was increased above if logo is used.
-		if keyCfg.LogoPath != "" {
+		if withLogo {
 			logoImg, ok := s.logoCache.Get(keyCfg.LogoPath)
 			if !ok {
 				loaded, err := loadLogoImage(keyCfg.LogoPath)
@@ -371,8 +379,16 @@ func (s *Server) handleSEPA(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Scheme marks (e.g. the Swiss cross) go on last so palettes cannot recolor them.
+	marked, err := qr.ApplySchemeMarks(cleaned.Scheme, payload, opt, quietZone, pngBytes)
+	if err != nil {
+		s.writeError(w, r, CodeQREncodeFailed, "qr encode failed", "")
+		return
+	}
+	pngBytes = marked
+
 	s.pngCache.Set(cacheKey, pngBytes)
-	s.writePNG(w, r, pngBytes)
+	s.writePNG(w, r, pngBytes, cleaned.Warnings)
 }
 
 func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
@@ -390,8 +406,10 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 		s.writeJSONError(w, CodeUnauthorized, "unauthorized", "", requestIDFromContext(r.Context()))
 		return
 	}
+	var keyCfg keys.KeyConfig
 	if !isPublic {
-		if _, ok := s.keys.Get(apiKey); !ok {
+		var ok bool
+		if keyCfg, ok = s.keys.Get(apiKey); !ok {
 			s.writeJSONError(w, CodeUnauthorized, "unauthorized", "", requestIDFromContext(r.Context()))
 			return
 		}
@@ -402,36 +420,98 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 	var in validate.Input
 	if err := dec.Decode(&in); err != nil {
 		s.logLimiter.Logf(string(CodeInvalidJSON), "validate: invalid json body: %v", err)
-		s.writeJSONValidation(w, false, CodeInvalidJSON, "invalid json body", "", requestIDFromContext(r.Context()))
+		s.writeJSONValidation(w, CodeInvalidJSON, "invalid json body", "", requestIDFromContext(r.Context()))
 		return
 	}
 	var extra any
 	if err := dec.Decode(&extra); err != io.EOF {
 		s.logLimiter.Logf(string(CodeInvalidJSON), "validate: invalid json body: trailing data")
-		s.writeJSONValidation(w, false, CodeInvalidJSON, "invalid json body", "", requestIDFromContext(r.Context()))
+		s.writeJSONValidation(w, CodeInvalidJSON, "invalid json body", "", requestIDFromContext(r.Context()))
 		return
 	}
-	_, err := validate.CleanAndValidate(in)
+	applyKeyDefaults(&in, keyCfg)
+	cleaned, err := validate.CleanAndValidate(in)
 	if err != nil {
-		s.logLimiter.Logf(string(CodeInvalidInput), "validate: invalid input: %v", err)
-		field := fieldFromValidationError(err.Error())
-		s.writeJSONValidation(w, false, CodeInvalidInput, err.Error(), field, requestIDFromContext(r.Context()))
+		code := validationErrorCode(err)
+		s.logLimiter.Logf(string(code), "validate: invalid input: %v", err)
+		field := fieldFromValidationError(err)
+		extra := map[string]any{}
+		if fieldErrs := validate.FieldErrors(err); fieldErrs != nil {
+			extra["errors"] = fieldErrs
+			if slices.ContainsFunc(fieldErrs, func(fe *validate.FieldError) bool { return fe.Field == "iban" }) {
+				if suggestions := validate.SuggestIBANs(in.IBAN); len(suggestions) > 0 {
+					extra["suggestions"] = suggestions
+				}
+			}
+		}
+		var tooLarge *validate.PayloadTooLargeError
+		if errors.As(err, &tooLarge) {
+			extra["byte_usage"] = tooLarge.Usage
+		}
+		s.writeJSONValidationWith(w, code, err.Error(), field, requestIDFromContext(r.Context()), extra)
 		return
 	}
-	s.writeJSONValidation(w, true, "", "", "", requestIDFromContext(r.Context()))
+	s.writeJSONValidationOK(w, cleaned, requestIDFromContext(r.Context()))
 }
 
-func (s *Server) writeJSONValidation(w http.ResponseWriter, ok bool, code ErrorCode, details, field, reqID string) {
+// handlePurposeCodes lists the ISO 20022 purpose codes accepted in `purpose`.
+func (s *Server) handlePurposeCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		s.writeJSONError(w, CodeMethodNotAllowed, "", "", requestIDFromContext(r.Context()))
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
-	if ok {
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if r.Method == http.MethodHead {
 		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]any{
-			"ok":         true,
-			"request_id": reqID,
-		})
 		return
 	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"ok":            true,
+		"purpose_codes": validate.PurposeCodes(),
+		"request_id":    requestIDFromContext(r.Context()),
+	})
+}
+
+func (s *Server) writeJSONValidationOK(w http.ResponseWriter, cleaned *validate.Clean, reqID string) {
+	resp := map[string]any{
+		"ok":                   true,
+		"scheme":               cleaned.Scheme,
+		"version":              cleaned.Version,
+		"charset":              cleaned.Charset,
+		"open_amount":          cleaned.OpenAmount,
+		"currency":             cleaned.Currency,
+		"remittance_reference": cleaned.RemittanceReference,
+		"transliterations":     cleaned.Transliterations,
+		"warnings":             cleaned.Warnings,
+		"request_id":           reqID,
+	}
+	if cleaned.ReferenceType != "" {
+		resp["reference_type"] = cleaned.ReferenceType
+	}
+	if cleaned.BezahlCodeKind != "" {
+		resp["bezahlcode_kind"] = cleaned.BezahlCodeKind
+	}
+	// The byte budget only exists for EPC payloads.
+	if cleaned.Scheme == validate.SchemeEPCSCT {
+		resp["byte_usage"] = cleaned.ByteUsage
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) writeJSONValidation(w http.ResponseWriter, code ErrorCode, details, field, reqID string) {
+	s.writeJSONValidationWith(w, code, details, field, reqID, nil)
+}
+
+// writeJSONValidationWith writes the validation error contract plus any
+// extra diagnostic fields (which never override the stable keys).
+func (s *Server) writeJSONValidationWith(w http.ResponseWriter, code ErrorCode, details, field, reqID string, extra map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
 	if code == "" {
 		code = CodeInvalidInput
 	}
@@ -439,13 +519,16 @@ func (s *Server) writeJSONValidation(w http.ResponseWriter, ok bool, code ErrorC
 	if details == "" {
 		details = string(code)
 	}
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"ok":         false,
-		"error_code": string(code),
-		"details":    details,
-		"field":      field,
-		"request_id": reqID,
-	})
+	resp := map[string]any{}
+	for k, v := range extra {
+		resp[k] = v
+	}
+	resp["ok"] = false
+	resp["error_code"] = string(code)
+	resp["details"] = details
+	resp["field"] = field
+	resp["request_id"] = reqID
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func clientIP(r *http.Request) string {
@@ -582,6 +665,26 @@ func buildCacheKey(isPublic bool, cleaned *validate.Clean, keyCfg keys.KeyConfig
 		b.WriteString(fmt.Sprintf("%d", keyCfg.QuietZone))
 		b.WriteString("|")
 	}
+	b.WriteString(cleaned.Scheme)
+	b.WriteString("|")
+	b.WriteString(cleaned.Version)
+	b.WriteString("|")
+	b.WriteString(fmt.Sprintf("%d", cleaned.Charset))
+	b.WriteString("|")
+	b.WriteString(cleaned.Currency)
+	b.WriteString("|")
+	b.WriteString(cleaned.ReferenceType)
+	for _, v := range []string{cleaned.DueDate, cleaned.VariableSymbol, cleaned.ConstantSymbol, cleaned.SpecificSymbol, cleaned.TransferType, cleaned.ValidUntil, cleaned.ExecutionDate, cleaned.Period} {
+		b.WriteString("|")
+		b.WriteString(v)
+	}
+	for _, a := range []validate.Address{cleaned.Creditor, cleaned.Debtor} {
+		for _, v := range []string{a.Name, a.Street, a.BuildingNumber, a.PostalCode, a.Town, a.Country} {
+			b.WriteString("|")
+			b.WriteString(v)
+		}
+	}
+	b.WriteString("|")
 	b.WriteString(cleaned.Name)
 	b.WriteString("|")
 	b.WriteString(cleaned.IBAN)
@@ -596,7 +699,11 @@ func buildCacheKey(isPublic bool, cleaned *validate.Clean, keyCfg keys.KeyConfig
 	b.WriteString("|")
 	b.WriteString(cleaned.Information)
 	b.WriteString("|")
-	b.WriteString(fmt.Sprintf("%d", cleaned.AmountCents))
+	if cleaned.OpenAmount {
+		b.WriteString("open")
+	} else {
+		b.WriteString(fmt.Sprintf("%d", cleaned.AmountCents))
+	}
 	b.WriteString("|")
 	b.WriteString(fmt.Sprintf("%d", opt.Size))
 	b.WriteString("|")
@@ -622,19 +729,41 @@ func etagForBytes(b []byte) string {
 	return hex.EncodeToString(sum[:])
 }
 
-func (s *Server) writePNG(w http.ResponseWriter, r *http.Request, pngBytes []byte) {
+func (s *Server) writePNG(w http.ResponseWriter, r *http.Request, pngBytes []byte, warnings []validate.Warning) {
 	w.Header().Set("Content-Type", "image/png")
 	w.Header().Set("Cache-Control", s.cfg.CacheControl)
 	w.Header().Set("ETag", `"`+etagForBytes(pngBytes)+`"`)
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pngBytes)))
 	w.Header().Set("Accept-Ranges", "bytes")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if len(warnings) > 0 {
+		w.Header().Set("X-SepaQX-Warnings", warningsHeader(warnings))
+	}
 	if r.Method == http.MethodHead {
 		return
 	}
 	_, _ = w.Write(pngBytes)
 }
 
+// warningsHeader encodes warnings as a JSON array for X-SepaQX-Warnings.
+// Non-ASCII characters are escaped so the value stays a valid header.
+func warningsHeader(warnings []validate.Warning) string {
+	raw, _ := json.Marshal(warnings)
+	var b strings.Builder
+	for _, r := range string(raw) {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case r > 0xFFFF:
+			r1, r2 := utf16.EncodeRune(r)
+			fmt.Fprintf(&b, `\u%04x\u%04x`, r1, r2)
+		default:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		}
+	}
+	return b.String()
+}
+
 func (s *Server) writeErrorPNG(w http.ResponseWriter, r *http.Request, status int, code string) {
 	if status < 400 {
 		status = http.StatusBadRequest
@@ -688,67 +817,131 @@ func newRequestID() string {
 	return hex.EncodeToString(b[:])
 }
 
-func fieldFromValidationError(msg string) string {
-	if strings.HasPrefix(msg, "unsupported currency: ") {
-		return "amount"
+// validationErrorCode maps a validation error to its API error code. Only
+// the first failed check decides the code.
+func validationErrorCode(err error) ErrorCode {
+	var fe *validate.FieldError
+	if errors.As(err, &fe) {
+		switch fe.Code {
+		case validate.CodePayloadTooLarge:
+			return CodePayloadTooLarge
+		case validate.CodeNonSEPAIBAN:
+			return CodeNonSEPAIBAN
+		}
 	}
-	if msg == "unsupported amount_format" {
-		return "amount_format"
+	return CodeInvalidInput
+}
+
+// fieldFromValidationError names the input field of the first failed check.
+// Validation errors carry it; query parsing errors are matched by message.
+func fieldFromValidationError(err error) string {
+	var fe *validate.FieldError
+	if errors.As(err, &fe) {
+		return fe.Field
 	}
+	msg := err.Error()
 	if strings.HasPrefix(msg, "duplicate query parameter: ") {
 		return strings.TrimSpace(strings.TrimPrefix(msg, "duplicate query parameter: "))
 	}
 	switch msg {
-	case "unsupported scheme":
-		return "scheme"
-	case "name is required":
-		return "name"
-	case "iban is required", "invalid iban":
-		return "iban"
-	case "bic is required", "invalid bic":
+	case "invalid open_amount":
+		return "open_amount"
+	case "invalid bic_from_iban":
 		return "bic"
-	case "amount is required", "invalid amount", "amount must be > 0", "amount too large":
-		return "amount"
-	case "remittance_reference and remittance_text are mutually exclusive":
-		return "remittance_reference"
 	default:
 		return ""
 	}
 }
 
-func inputFromQuery(q url.Values) (validate.Input, error) {
-	var in validate.Input
-	var err error
-
-	if in.Scheme, err = singleQueryParam(q, "scheme"); err != nil {
-		return validate.Input{}, err
-	}
-	if in.Name, err = singleQueryParam(q, "name"); err != nil {
-		return validate.Input{}, err
-	}
-	if in.IBAN, err = singleQueryParam(q, "iban"); err != nil {
-		return validate.Input{}, err
+// applyKeyDefaults fills request fields the client left empty with the
+// per-key defaults from keys.json. Public requests use a zero KeyConfig.
+func applyKeyDefaults(in *validate.Input, keyCfg keys.KeyConfig) {
+	in.AllowNonSEPA = keyCfg.AllowNonSEPAIBAN
+	if strings.TrimSpace(in.IBANFormat) == "" {
+		in.IBANFormat = keyCfg.IBANFormat
 	}
-	if in.BIC, err = singleQueryParam(q, "bic"); err != nil {
-		return validate.Input{}, err
+
+	// EPC defaults would be rejected by other schemes.
+	if scheme := strings.ToLower(strings.TrimSpace(in.Scheme)); scheme != "" && scheme != validate.SchemeEPCSCT {
+		return
 	}
-	if in.Amount, err = singleQueryParam(q, "amount"); err != nil {
-		return validate.Input{}, err
+	if strings.TrimSpace(in.Version) == "" {
+		in.Version = keyCfg.EPCVersion
 	}
-	if in.AmountFormat, err = singleQueryParam(q, "amount_format"); err != nil {
-		return validate.Input{}, err
+	if strings.TrimSpace(in.Charset) == "" {
+		in.Charset = keyCfg.EPCCharset
 	}
-	if in.Purpose, err = singleQueryParam(q, "purpose"); err != nil {
-		return validate.Input{}, err
+	if strings.TrimSpace(in.SEPACharset) == "" {
+		in.SEPACharset = keyCfg.SEPACharset
 	}
-	if in.RemittanceReference, err = singleQueryParam(q, "remittance_reference"); err != nil {
-		return validate.Input{}, err
+}
+
+func inputFromQuery(q url.Values) (validate.Input, error) {
+	var in validate.Input
+	var openAmount, bicFromIBAN string
+
+	// Query parameters use the JSON field names, in the order they are checked.
+	params := []struct {
+		key string
+		dst *string
+	}{
+		{"scheme", &in.Scheme},
+		{"version", &in.Version},
+		{"charset", &in.Charset},
+		{"sepa_charset", &in.SEPACharset},
+		{"name", &in.Name},
+		{"iban", &in.IBAN},
+		{"bic", &in.BIC},
+		{"bic_from_iban", &bicFromIBAN},
+		{"iban_format", &in.IBANFormat},
+		{"amount", &in.Amount},
+		{"amount_format", &in.AmountFormat},
+		{"open_amount", &openAmount},
+		{"currency", &in.Currency},
+		{"purpose", &in.Purpose},
+		{"remittance_reference", &in.RemittanceReference},
+		{"reference_type", &in.ReferenceType},
+		{"rf_from_invoice", &in.RFFromInvoice},
+		{"remittance_text", &in.RemittanceText},
+		{"information", &in.Information},
+		{"due_date", &in.DueDate},
+		{"variable_symbol", &in.VariableSymbol},
+		{"constant_symbol", &in.ConstantSymbol},
+		{"specific_symbol", &in.SpecificSymbol},
+		{"transfer_type", &in.TransferType},
+		{"valid_until", &in.ValidUntil},
+		{"execution_date", &in.ExecutionDate},
+		{"period", &in.Period},
+		{"creditor_street", &in.CreditorStreet},
+		{"creditor_building_number", &in.CreditorBuildingNumber},
+		{"creditor_postal_code", &in.CreditorPostalCode},
+		{"creditor_town", &in.CreditorTown},
+		{"creditor_country", &in.CreditorCountry},
+		{"debtor_name", &in.DebtorName},
+		{"debtor_street", &in.DebtorStreet},
+		{"debtor_building_number", &in.DebtorBuildingNumber},
+		{"debtor_postal_code", &in.DebtorPostalCode},
+		{"debtor_town", &in.DebtorTown},
+		{"debtor_country", &in.DebtorCountry},
+	}
+	for _, p := range params {
+		v, err := singleQueryParam(q, p.key)
+		if err != nil {
+			return validate.Input{}, err
+		}
+		*p.dst = v
 	}
-	if in.RemittanceText, err = singleQueryParam(q, "remittance_text"); err != nil {
-		return validate.Input{}, err
+	if openAmount != "" {
+		var err error
+		if in.OpenAmount, err = strconv.ParseBool(openAmount); err != nil {
+			return validate.Input{}, fmt.Errorf("invalid open_amount")
+		}
 	}
-	if in.Information, err = singleQueryParam(q, "information"); err != nil {
-		return validate.Input{}, err
+	if bicFromIBAN != "" {
+		var err error
+		if in.BICFromIBAN, err = strconv.ParseBool(bicFromIBAN); err != nil {
+			return validate.Input{}, fmt.Errorf("invalid bic_from_iban")
+		}
 	}
 
 	return in, nil
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/safe-cap/sepaqx/config"
+	"github.com/safe-cap/sepaqx/keys"
+	"github.com/safe-cap/sepaqx/validate"
+)
+
+func newTestServer(t *testing.T, keysJSON string) http.Handler {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(keysJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := keys.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("keys.LoadFromFile: %v", err)
+	}
+	return New(cfg, store, nil).httpSrv.Handler
+}
+
+func TestHandleValidate_IBANFormat(t *testing.T) {
+	h := newTestServer(t, `{"keys":[{"key":"ocr-key","name":"ocr","iban_format":"ocr_lenient"}]}`)
+	validateReq := func(apiKey, body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/sepa-qr/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+	const scanned = `"name":"Example GmbH","iban":"IBAN: DE12-5OO1-O517-O648-4898-9O","bic":"INGDDEFFXXX","amount":"1"`
+
+	validate.SetIBANLenientOCR(true)
+	defer validate.SetIBANLenientOCR(false)
+	tests := []struct {
+		name, apiKey, body string
+		status             int
+		want               string
+	}{
+		{"not_requested", "", `{` + scanned + `}`, http.StatusBadRequest, `"field":"iban"`},
+		{"request_opt_in", "", `{` + scanned + `,"iban_format":"ocr_lenient"}`, http.StatusOK, `"code":"ocr_corrected"`},
+		{"key_opt_in", "ocr-key", `{` + scanned + `}`, http.StatusOK, `"code":"ocr_corrected"`},
+		{"request_overrides_key", "ocr-key", `{` + scanned + `,"iban_format":"strict"}`, http.StatusBadRequest, `"field":"iban"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := validateReq(tt.apiKey, tt.body)
+			if status != tt.status || !strings.Contains(body, tt.want) {
+				t.Fatalf("got %d %s, want %d with %s", status, body, tt.status, tt.want)
+			}
+		})
+	}
+
+	validate.SetIBANLenientOCR(false)
+	status, body := validateReq("ocr-key", `{`+scanned+`}`)
+	if status != http.StatusBadRequest || !strings.Contains(body, `"field":"iban_format"`) {
+		t.Fatalf("expected unsupported iban_format while IBAN_LENIENT_OCR is off, got %d %s", status, body)
+	}
+}
@@ -121,6 +121,103 @@ expect_status 200 "$(post_json "$(payload_with "\"amount\":\"49,90\",\"amount_fo
 expect_status 200 "$(post_json "$(payload_with "\"remittance_reference\":\"RF18539007547034\"")")" "POST remittance_reference"
 expect_status 200 "$(post_json "$(payload_with "\"remittance_text\":\"Order 2026-0001\"")")" "POST remittance_text"
 expect_status 200 "$(post_json "$(payload_with "\"information\":\"Invoice 0001\"")")" "POST information"
+expect_status 200 "$(post_json "{\"name\":\"${valid_name}\",\"iban\":\"${valid_iban}\",\"bic\":\"${valid_bic}\",\"open_amount\":true}")" "POST open_amount"
+
+echo "POST swiss_qr"
+swiss_payload="{\"scheme\":\"swiss_qr\",\"name\":\"Robert Schneider AG\",\"iban\":\"CH4431999123000889012\",\"amount\":\"1949.75\",\"remittance_reference\":\"210000000003139471430009017\",\"creditor_postal_code\":\"2501\",\"creditor_town\":\"Biel\",\"creditor_country\":\"CH\"}"
+expect_status 200 "$(post_json "${swiss_payload}")" "POST swiss_qr"
+expect_status 400 "$(post_json "{\"scheme\":\"swiss_qr\",\"name\":\"Robert Schneider AG\",\"iban\":\"CH4431999123000889012\",\"amount\":\"1949.75\",\"creditor_postal_code\":\"2501\",\"creditor_town\":\"Biel\",\"creditor_country\":\"CH\"}")" "POST swiss_qr QR-IBAN without reference"
+resp="$(post_validate "${swiss_payload}")"
+if ! printf "%s" "${resp}" | grep -q '"reference_type":"QRR"'; then
+  echo "FAIL: validate swiss_qr reference_type"
+  failures=$((failures + 1))
+else
+  echo "OK: validate swiss_qr reference_type"
+fi
+
+echo "POST cz_spd"
+spd_payload="{\"scheme\":\"cz_spd\",\"name\":\"Example s.r.o.\",\"iban\":\"CZ6508000000192000145399\",\"amount\":\"450.00\",\"variable_symbol\":\"1234567890\",\"due_date\":\"2026-11-30\"}"
+expect_status 200 "$(post_json "${spd_payload}")" "POST cz_spd"
+expect_status 400 "$(post_json "{\"scheme\":\"cz_spd\",\"name\":\"Example s.r.o.\",\"iban\":\"CZ6508000000192000145399\",\"amount\":\"450.00\",\"variable_symbol\":\"12A\"}")" "POST cz_spd invalid variable_symbol"
+expect_status 400 "$(post_json "$(payload_with "\"variable_symbol\":\"123\"")")" "POST epc_sct variable_symbol"
+
+echo "POST sk_pay_by_square"
+expect_status 200 "$(post_json "{\"scheme\":\"sk_pay_by_square\",\"name\":\"Example s.r.o.\",\"iban\":\"SK3112000000198742637541\",\"amount\":\"125.50\",\"variable_symbol\":\"2026001234\",\"due_date\":\"2026-11-30\"}")" "POST sk_pay_by_square"
+expect_status 400 "$(post_json "{\"scheme\":\"sk_pay_by_square\",\"name\":\"Example s.r.o.\",\"iban\":\"SK3112000000198742637541\",\"amount\":\"125.50\",\"constant_symbol\":\"03080\"}")" "POST sk_pay_by_square invalid constant_symbol"
+
+echo "POST si_upn"
+upn_payload="{\"scheme\":\"si_upn\",\"name\":\"Podjetje d.o.o.\",\"iban\":\"SI56191000000123438\",\"amount\":\"125.50\",\"remittance_reference\":\"SI121234567890120\",\"remittance_text\":\"Invoice 17\",\"creditor_street\":\"Slovenska cesta 1\",\"creditor_town\":\"1000 Ljubljana\"}"
+expect_status 200 "$(post_json "${upn_payload}")" "POST si_upn"
+expect_status 400 "$(post_json "{\"scheme\":\"si_upn\",\"name\":\"Podjetje d.o.o.\",\"iban\":\"SI56191000000123438\",\"amount\":\"125.50\",\"remittance_reference\":\"SI121234567890121\",\"remittance_text\":\"Invoice 17\",\"creditor_street\":\"Slovenska cesta 1\",\"creditor_town\":\"1000 Ljubljana\"}")" "POST si_upn invalid reference"
+resp="$(post_validate "${upn_payload}")"
+if ! printf "%s" "${resp}" | grep -q '"reference_type":"SI12"'; then
+  echo "FAIL: validate si_upn reference_type"
+  failures=$((failures + 1))
+else
+  echo "OK: validate si_upn reference_type"
+fi
+
+echo "POST hu_mnb"
+expect_status 200 "$(post_json "{\"scheme\":\"hu_mnb\",\"name\":\"Pelda Kft.\",\"iban\":\"HU42117730161111101800000000\",\"bic\":\"OTPVHUHB\",\"amount\":\"12500\",\"valid_until\":\"2026-11-30T18:00:00+01:00\"}")" "POST hu_mnb"
+expect_status 400 "$(post_json "{\"scheme\":\"hu_mnb\",\"name\":\"Pelda Kft.\",\"iban\":\"HU42117730161111101800000000\",\"bic\":\"OTPVHUHB\",\"amount\":\"12500\"}")" "POST hu_mnb without valid_until"
+
+echo "POST hr_hub3"
+hub3_payload="{\"scheme\":\"hr_hub3\",\"name\":\"Primatelj d.o.o.\",\"iban\":\"HR1210010051863000160\",\"amount\":\"125.50\",\"remittance_reference\":\"HR011234-5678\",\"remittance_text\":\"Invoice 17\"}"
+expect_status 200 "$(post_json "${hub3_payload}")" "POST hr_hub3"
+expect_status 400 "$(post_json "{\"scheme\":\"hr_hub3\",\"name\":\"Primatelj d.o.o.\",\"iban\":\"HR1210010051863000160\",\"amount\":\"125.50\",\"remittance_reference\":\"HR01ABC\",\"remittance_text\":\"Invoice 17\"}")" "POST hr_hub3 invalid reference"
+
+echo "POST fi_barcode"
+expect_status 200 "$(post_json "{\"scheme\":\"fi_barcode\",\"iban\":\"FI7944052020036082\",\"amount\":\"4883.15\",\"remittance_reference\":\"868516259619897\",\"due_date\":\"2010-06-12\"}")" "POST fi_barcode"
+expect_status 400 "$(post_json "{\"scheme\":\"fi_barcode\",\"iban\":\"FI7944052020036082\",\"amount\":\"4883.15\",\"remittance_reference\":\"868516259619898\"}")" "POST fi_barcode invalid reference"
+
+echo "POST bezahlcode"
+expect_status 200 "$(post_json "{\"scheme\":\"bezahlcode\",\"name\":\"Verein e.V.\",\"iban\":\"${valid_iban}\",\"amount\":\"5.00\",\"remittance_text\":\"Beitrag\",\"execution_date\":\"2026-11-01\",\"period\":\"1M\"}")" "POST bezahlcode"
+expect_status 400 "$(post_json "{\"scheme\":\"bezahlcode\",\"name\":\"Verein e.V.\",\"iban\":\"${valid_iban}\",\"amount\":\"5.00\",\"period\":\"1Y\"}")" "POST bezahlcode invalid period"
+
+echo "POST reference_type"
+resp="$(post_validate "$(payload_with "\"reference_type\":\"be_ogm\",\"remittance_reference\":\"123/4567/89002\"")")"
+if ! printf "%s" "${resp}" | grep -q '"remittance_reference":"+++123/4567/89002+++"'; then
+  echo "FAIL: validate be_ogm canonical form"
+  failures=$((failures + 1))
+else
+  echo "OK: validate be_ogm canonical form"
+fi
+resp="$(post_validate "$(payload_with "\"reference_type\":\"no_kid\",\"remittance_reference\":\"1234567\"")")"
+if ! printf "%s" "${resp}" | grep -q '"field":"remittance_reference"'; then
+  echo "FAIL: validate no_kid check digit field"
+  failures=$((failures + 1))
+else
+  echo "OK: validate no_kid check digit field"
+fi
+
+echo "POST non-SEPA iban"
+expect_status 400 "$(post_json "{\"name\":\"${valid_name}\",\"iban\":\"SA0380000000608010167519\",\"bic\":\"RIBLSARI\",\"amount\":\"${valid_amount}\"}")" "POST non-SEPA iban"
+resp="$(post_validate "{\"name\":\"${valid_name}\",\"iban\":\"SA0380000000608010167519\",\"bic\":\"RIBLSARI\",\"amount\":\"${valid_amount}\"}")"
+if ! printf "%s" "${resp}" | grep -q '"non_sepa_iban"'; then
+  echo "FAIL: validate non_sepa_iban code"
+  failures=$((failures + 1))
+else
+  echo "OK: validate non_sepa_iban code"
+fi
+
+echo "POST validate iban suggestions"
+resp="$(post_validate "{\"name\":\"${valid_name}\",\"iban\":\"DE12500105170648489809\",\"bic\":\"${valid_bic}\",\"amount\":\"${valid_amount}\"}")"
+if ! printf "%s" "${resp}" | grep -q '"suggestions":\["DE12500105170648489890"'; then
+  echo "FAIL: validate iban suggestions"
+  failures=$((failures + 1))
+else
+  echo "OK: validate iban suggestions"
+fi
+
+echo "POST validate all field errors"
+resp="$(post_validate "{\"iban\":\"DE00\",\"bic\":\"${valid_bic}\",\"amount\":\"-1\"}")"
+if ! printf "%s" "${resp}" | grep -q '"field":"name"' || ! printf "%s" "${resp}" | grep -q '"errors":\[{"code":"required","field":"name"' ||
+  ! printf "%s" "${resp}" | grep -q '"field":"iban","message"' || ! printf "%s" "${resp}" | grep -q '"field":"amount","message"'; then
+  echo "FAIL: validate errors array"
+  failures=$((failures + 1))
+else
+  echo "OK: validate errors array"
+fi
 
 echo "POST invalid combinations"
 expect_status 400 "$(post_json "$(payload_with "\"remittance_reference\":\"RF18539007547034\",\"remittance_text\":\"Both\"")")" "POST remittance both"
@@ -141,6 +238,8 @@ expect_status 400 "$(post_json "{\"scheme\":\"pix\",\"name\":\"${valid_name}\",\
 expect_status 400 "$(post_json "{\"name\":\"${valid_name}\",\"iban\":\"${valid_iban}\",\"bic\":\"${valid_bic}\",\"amount\":\"49,90\",\"amount_format\":\"eur_dot\"}")" "POST amount_format mismatch"
 expect_status 400 "$(post_json "{\"name\":\"${valid_name}\",\"iban\":\"${valid_iban}\",\"bic\":\"${valid_bic}\",\"amount\":\"49.90\",\"amount_format\":\"custom_profile\"}")" "POST amount_format unsupported"
 expect_status 400 "$(post_json "{\"name\":\"${valid_name}\",\"iban\":\"${valid_iban}\",\"bic\":\"${valid_bic}\",\"amount\":\"1O,5\"}")" "POST amount OCR strict mode"
+expect_status 400 "$(post_json "$(payload_with "\"purpose\":\"XXXX\"")")" "POST purpose unknown"
+expect_status 400 "$(post_json "$(payload_with "\"open_amount\":true")")" "POST open_amount with amount"
 expect_status 400 "$(post_json "{bad-json}")" "POST invalid json"
 
 echo "Validation API"
@@ -177,6 +276,18 @@ else
   echo "OK: validate invalid input body"
 fi
 
+echo "Purpose codes API"
+resp="$(curl -sS -w "\n%{http_code}" "${BASE_URL}/sepa-qr/purpose-codes")"
+body="$(printf "%s" "${resp}" | sed '$d')"
+code="$(printf "%s" "${resp}" | tail -n 1)"
+expect_status 200 "${code}" "GET /sepa-qr/purpose-codes"
+if ! printf "%s" "${body}" | grep -q '"code":"GDDS"'; then
+  echo "FAIL: purpose codes body"
+  failures=$((failures + 1))
+else
+  echo "OK: purpose codes body"
+fi
+
 echo "Validation rate limit"
 cleanup
 trap cleanup EXIT
@@ -246,6 +357,19 @@ qs_comma="name=Example%20GmbH&iban=${valid_iban}&bic=${valid_bic}&amount=49,90"
 expect_status 200 "$(get_query "${qs_comma}&amount_format=eur_comma")" "GET amount_format eur_comma"
 expect_status 400 "$(get_query "${qs_comma}&amount_format=eur_dot")" "GET amount_format mismatch"
 expect_status 400 "$(get_query "${qs}&scheme=pix")" "GET scheme unsupported"
+expect_status 200 "$(get_query "name=Example%20GmbH&iban=${valid_iban}&bic=${valid_bic}&open_amount=true")" "GET open_amount"
+expect_status 400 "$(get_query "name=Example%20GmbH&iban=${valid_iban}&bic=${valid_bic}&open_amount=maybe")" "GET open_amount invalid"
+expect_status 400 "$(get_query "name=Example%20GmbH&iban=${valid_iban}&bic_from_iban=maybe")" "GET bic_from_iban invalid"
+expect_status 200 "$(get_query "name=M%C3%BCller%20%26%20S%C3%B6hne&iban=${valid_iban}&bic=${valid_bic}&amount=10&sepa_charset=transliterate")" "GET sepa_charset transliterate"
+expect_status 400 "$(get_query "name=M%C3%BCller&iban=${valid_iban}&bic=${valid_bic}&amount=10&sepa_charset=strict")" "GET sepa_charset strict"
+
+hdrs="$(get_headers "name=$(printf 'N%.0s' $(seq 75))&iban=${valid_iban}&bic=${valid_bic}&amount=10")"
+if ! printf "%s" "${hdrs}" | grep -qi '^x-sepaqx-warnings: \[{"field":"name","code":"truncated"'; then
+  echo "FAIL: GET truncation warnings header"
+  failures=$((failures + 1))
+else
+  echo "OK: GET truncation warnings header"
+fi
 
 echo "HEAD bare should succeed without params"
 expect_status 200 "$(head_bare)" "HEAD /sepa-qr"
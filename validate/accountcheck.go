@@ -0,0 +1,1021 @@
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// German account numbers carry a check digit computed with one of the
+// Bundesbank check methods (Prüfzifferberechnungsmethoden 00..E4). The
+// method of each bank comes from the BLZ file, so the deep check needs a
+// directory built with sepaqx bic-directory --bundesbank.
+
+var accountCheck atomic.Bool
+
+// SetAccountCheck turns the German account check digit test in CheckIBAN
+// on or off.
+func SetAccountCheck(enabled bool) {
+	accountCheck.Store(enabled)
+}
+
+// account holds the ten digits of a German account number, left-padded
+// with zeros. Method descriptions count positions 1..10 from the left.
+type account [10]int
+
+func (a *account) at(pos int) int { return a[pos-1] }
+
+func (a *account) value() int64 {
+	var v int64
+	for _, d := range a {
+		v = v*10 + int64(d)
+	}
+	return v
+}
+
+// shift moves the digits n positions to the left and fills with zeros.
+func (a account) shift(n int) account {
+	var out account
+	copy(out[:], a[n:])
+	return out
+}
+
+// weightedSum multiplies the digits from position from down to position
+// to (walking left) with weights, repeating the weights as needed. With
+// cross the digit sum of each product is added instead of the product.
+func (a *account) weightedSum(from, to int, weights []int, cross bool) int {
+	sum := 0
+	for i, pos := 0, from; pos >= to; i, pos = i+1, pos-1 {
+		p := a.at(pos) * weights[i%len(weights)]
+		if cross {
+			p = p/10 + p%10
+		}
+		sum += p
+	}
+	return sum
+}
+
+// mod10 checks the digit at position check against 10 minus the last
+// digit of the weighted sum.
+func (a *account) mod10(from, to int, weights []int, cross bool, check int) bool {
+	return (10-a.weightedSum(from, to, weights, cross)%10)%10 == a.at(check)
+}
+
+// mod11 checks the digit at position check against 11 minus the remainder
+// of the weighted sum. Remainder 0 gives 0; remainder 1 gives 0 as well
+// when oneIsZero is set and makes the account invalid otherwise.
+func (a *account) mod11(from, to int, weights []int, oneIsZero bool, check int) bool {
+	switch r := a.weightedSum(from, to, weights, false) % 11; r {
+	case 0:
+		return a.at(check) == 0
+	case 1:
+		return oneIsZero && a.at(check) == 0
+	default:
+		return 11-r == a.at(check)
+	}
+}
+
+// modN checks the digit at position check against n minus the remainder
+// of the weighted sum modulo n (remainder 0 gives 0).
+func (a *account) modN(n, from, to int, weights []int, cross bool, check int) bool {
+	return (n-a.weightedSum(from, to, weights, cross)%n)%n == a.at(check)
+}
+
+// m10h sums the digits at positions 9..1 after the transformation table
+// of methods 27 and 29 (rows 1, 2, 3, 4, repeated).
+func (a *account) m10h() bool {
+	table := [4][10]int{
+		{0, 1, 5, 9, 3, 7, 4, 8, 2, 6},
+		{0, 1, 7, 6, 9, 8, 3, 2, 5, 4},
+		{0, 1, 8, 4, 6, 2, 9, 5, 7, 3},
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	}
+	sum := 0
+	for i, pos := 0, 9; pos >= 1; i, pos = i+1, pos-1 {
+		sum += table[i%4][a.at(pos)]
+	}
+	return (10-sum%10)%10 == a.at(10)
+}
+
+var (
+	w21     = []int{2, 1}
+	w27     = []int{2, 3, 4, 5, 6, 7}
+	w29     = []int{2, 3, 4, 5, 6, 7, 8, 9}
+	w210    = []int{2, 3, 4, 5, 6, 7, 8, 9, 10}
+	w248510 = []int{2, 4, 8, 5, 10, 9, 7, 3, 6}
+)
+
+func method00(a *account) bool { return a.mod10(9, 1, w21, true, 10) }
+func method01(a *account) bool { return a.mod10(9, 1, []int{3, 7, 1}, false, 10) }
+func method02(a *account) bool { return a.mod11(9, 1, w29, false, 10) }
+func method03(a *account) bool { return a.mod10(9, 1, w21, false, 10) }
+func method04(a *account) bool { return a.mod11(9, 1, w27, false, 10) }
+func method05(a *account) bool { return a.mod10(9, 1, []int{7, 3, 1}, false, 10) }
+func method06(a *account) bool { return a.mod11(9, 1, w27, true, 10) }
+func method07(a *account) bool { return a.mod11(9, 1, w210, false, 10) }
+func method10(a *account) bool { return a.mod11(9, 1, w210, true, 10) }
+func method15(a *account) bool { return a.mod11(9, 6, w27[:4], true, 10) }
+func method18(a *account) bool { return a.mod10(9, 1, []int{3, 9, 7, 1}, false, 10) }
+func method19(a *account) bool { return a.mod11(9, 1, []int{2, 3, 4, 5, 6, 7, 8, 9, 1}, true, 10) }
+func method20(a *account) bool { return a.mod11(9, 1, []int{2, 3, 4, 5, 6, 7, 8, 9, 3}, true, 10) }
+func method28(a *account) bool { return a.mod11(7, 1, []int{2, 3, 4, 5, 6, 7, 8}, true, 8) }
+func method29(a *account) bool { return a.m10h() }
+func method32(a *account) bool { return a.mod11(9, 4, w27, true, 10) }
+func method33(a *account) bool { return a.mod11(9, 5, w27[:5], true, 10) }
+func method58(a *account) bool { return a.mod11(9, 5, w27[:5], false, 10) }
+
+func method17(a *account) bool {
+	r := (a.weightedSum(7, 2, w21, true) - 1) % 11
+	return (10-r)%10 == a.at(8)
+}
+
+func method21(a *account) bool {
+	sum := a.weightedSum(9, 1, w21, true)
+	for sum > 9 {
+		sum = sum/10 + sum%10
+	}
+	return (10-sum)%10 == a.at(10)
+}
+
+func method22(a *account) bool {
+	sum := 0
+	for i, pos := 0, 9; pos >= 1; i, pos = i+1, pos-1 {
+		sum += a.at(pos) * []int{3, 1}[i%2] % 10
+	}
+	return (10-sum%10)%10 == a.at(10)
+}
+
+func method27(a *account) bool {
+	if a.value() < 1000000000 {
+		return method00(a)
+	}
+	return a.m10h()
+}
+
+func method63(a *account) bool {
+	if a.at(1) != 0 {
+		return false
+	}
+	if a.mod10(7, 2, w21, true, 8) {
+		return true
+	}
+	return a.at(2) == 0 && a.at(3) == 0 && a.mod10(9, 4, w21, true, 10)
+}
+
+func method95(a *account) bool {
+	v := a.value()
+	for _, r := range [][2]int64{
+		{1, 1999999},
+		{9000000, 25999999},
+		{396000000, 499999999},
+		{700000000, 799999999},
+		{910000000, 989999999},
+	} {
+		if v >= r[0] && v <= r[1] {
+			return true
+		}
+	}
+	return method06(a)
+}
+
+// method51Ledger checks the ledger accounts (9 at position 3) shared by
+// methods 51, 73, 80, 81, 84, 87 and A8.
+func method51Ledger(a *account) bool {
+	return a.mod11(9, 3, w29[:7], true, 10) || method10(a)
+}
+
+func method68(a *account) bool {
+	if a.at(1) != 0 {
+		return a.at(4) == 9 && a.mod10(9, 4, w21, true, 10)
+	}
+	if v := a.value(); v >= 400000000 && v <= 499999999 {
+		return true
+	}
+	return method00(a) || a.mod10(9, 1, []int{2, 1, 2, 1, 2, 0, 0, 1, 2}, true, 10)
+}
+
+func method75(a *account) bool {
+	switch v := a.value(); {
+	case v >= 100000 && v <= 9999999:
+		return a.mod10(9, 5, w21, true, 10)
+	case v >= 100000000 && v <= 999999999:
+		if a.at(2) == 9 {
+			return a.mod10(7, 3, w21, true, 8)
+		}
+		return a.mod10(6, 2, w21, true, 7)
+	}
+	return false
+}
+
+func method83(a *account) bool {
+	if a.at(3) == 9 && a.at(4) == 9 {
+		return a.mod11(9, 3, w29[:7], true, 10)
+	}
+	return method32(a) || method33(a) || a.modN(7, 9, 5, w27[:5], false, 10)
+}
+
+// method87A is method A of 87, written out in the Bundesbank's pseudo
+// code.
+func method87A(a *account) bool {
+	tab1 := []int{0, 4, 3, 2, 6}
+	tab2 := []int{7, 1, 5, 9, 8}
+	k := *a
+	i := 4
+	for i < 10 && k.at(i) == 0 {
+		i++
+	}
+	c2, d2, a5 := i%2, 0, 0
+	for ; i < 10; i++ {
+		switch k[i-1] {
+		case 0:
+			k[i-1] = 5
+		case 1:
+			k[i-1] = 6
+		case 5:
+			k[i-1] = 10
+		case 6:
+			k[i-1] = 1
+		}
+		d := k[i-1]
+		if c2 == d2 {
+			if d > 5 {
+				if c2 == 0 && d2 == 0 {
+					c2, d2, a5 = 1, 1, a5+6-(d-6)
+				} else {
+					c2, d2, a5 = 0, 0, a5+d
+				}
+			} else if c2 == 0 && d2 == 0 {
+				c2, a5 = 1, a5+d
+			} else {
+				c2, a5 = 0, a5+d
+			}
+		} else {
+			if d > 5 {
+				if c2 == 0 {
+					c2, d2, a5 = 1, 0, a5-6+(d-6)
+				} else {
+					c2, d2, a5 = 0, 1, a5-d
+				}
+			} else if c2 == 0 {
+				c2, a5 = 1, a5-d
+			} else {
+				c2, a5 = 0, a5-d
+			}
+		}
+	}
+	for a5 < 0 || a5 > 4 {
+		if a5 > 4 {
+			a5 -= 5
+		} else {
+			a5 += 5
+		}
+	}
+	p := tab1[a5]
+	if d2 != 0 {
+		p = tab2[a5]
+	}
+	if p == a.at(10) {
+		return true
+	}
+	if a.at(4) != 0 {
+		return false
+	}
+	if p > 4 {
+		return p-5 == a.at(10)
+	}
+	return p+5 == a.at(10)
+}
+
+func method93(a *account) bool {
+	from, check := 5, 6
+	if a.value() < 1000000 {
+		from, check = 9, 10
+	}
+	return a.mod11(from, from-4, w27[:5], true, check) || a.modN(7, from, from-4, w27[:5], false, check)
+}
+
+// prefixedMod10 runs method 00 over prefix followed by the digits from
+// position from to 9.
+func (a *account) prefixedMod10(prefix string, from int) bool {
+	digits := make([]int, 0, len(prefix)+9)
+	for i := range prefix {
+		digits = append(digits, int(prefix[i]-'0'))
+	}
+	digits = append(digits, a[from-1:9]...)
+	sum := 0
+	for i, j := 0, len(digits)-1; j >= 0; i, j = i+1, j-1 {
+		p := digits[j] * w21[i%2]
+		sum += p/10 + p%10
+	}
+	return (10-sum%10)%10 == a.at(10)
+}
+
+// eserCheck runs methods 52 and 53 on the account number of the old ESER
+// system, built from bankPart, the account type x, the check digit p and
+// the remaining digits without leading zeros.
+func eserCheck(bankPart string, x, p int, rest []int) bool {
+	digits := make([]int, 0, 12)
+	for i := range bankPart {
+		digits = append(digits, int(bankPart[i]-'0'))
+	}
+	digits = append(digits, x, p)
+	for len(rest) > 0 && rest[0] == 0 {
+		rest = rest[1:]
+	}
+	digits = append(digits, rest...)
+	if len(digits) > 12 {
+		return false
+	}
+	weights := []int{2, 4, 8, 5, 10, 9, 7, 3, 6, 1, 2, 4}
+	sum := 0
+	for i, j := 0, len(digits)-1; j >= 0; i, j = i+1, j-1 {
+		sum += digits[j] * weights[i]
+	}
+	return sum%11 == 10
+}
+
+func method52(a *account, bankCode string) bool {
+	if a.at(1) == 9 {
+		return method20(a)
+	}
+	if a.at(1) != 0 || a.at(2) != 0 || a.at(3) == 0 || len(bankCode) != 8 || !isDigits(bankCode) {
+		return false
+	}
+	return eserCheck(bankCode[4:], a.at(3), a.at(4), a[4:])
+}
+
+func method53(a *account, bankCode string) bool {
+	if a.at(1) == 9 {
+		return method20(a)
+	}
+	if a.at(1) != 0 || a.at(2) == 0 || len(bankCode) != 8 || !isDigits(bankCode) {
+		return false
+	}
+	return eserCheck(bankCode[4:5]+string(rune('0'+a.at(3)))+bankCode[6:], a.at(2), a.at(4), a[4:])
+}
+
+// accountCheckMethods holds the methods that only need the account
+// number. Method 12 is not assigned.
+var accountCheckMethods = map[string]func(a *account) bool{
+	"00": method00,
+	"01": method01,
+	"02": method02,
+	"03": method03,
+	"04": method04,
+	"05": method05,
+	"06": method06,
+	"07": method07,
+	"08": func(a *account) bool { return a.value() < 60000 || method00(a) },
+	"09": func(a *account) bool { return true },
+	"10": method10,
+	"11": func(a *account) bool {
+		switch r := a.weightedSum(9, 1, w210, false) % 11; r {
+		case 0:
+			return a.at(10) == 0
+		case 1:
+			return a.at(10) == 9
+		default:
+			return 11-r == a.at(10)
+		}
+	},
+	"13": func(a *account) bool {
+		if a.mod10(7, 2, w21, true, 8) {
+			return true
+		}
+		s := a.shift(2)
+		return s.mod10(7, 2, w21, true, 8)
+	},
+	"14": func(a *account) bool { return a.mod11(9, 4, w27, false, 10) },
+	"15": method15,
+	"16": func(a *account) bool {
+		if a.weightedSum(9, 1, w27, false)%11 == 1 {
+			return a.at(9) == a.at(10)
+		}
+		return a.mod11(9, 1, w27, true, 10)
+	},
+	"17": method17,
+	"18": method18,
+	"19": method19,
+	"20": method20,
+	"21": method21,
+	"22": method22,
+	"23": func(a *account) bool {
+		if a.weightedSum(6, 1, w27, false)%11 == 1 {
+			return a.at(6) == a.at(7)
+		}
+		return a.mod11(6, 1, w27, true, 7)
+	},
+	"24": func(a *account) bool {
+		d := *a
+		switch d[0] {
+		case 3, 4, 5, 6:
+			d[0] = 0
+		case 9:
+			d[0], d[1], d[2] = 0, 0, 0
+		}
+		start := 0
+		for start < 9 && d[start] == 0 {
+			start++
+		}
+		sum := 0
+		for i, pos := 0, start; pos < 9; i, pos = i+1, pos+1 {
+			w := []int{1, 2, 3}[i%3]
+			sum += (d[pos]*w + w) % 11
+		}
+		return sum%10 == d[9]
+	},
+	"25": func(a *account) bool {
+		r := a.weightedSum(9, 2, w29, false) % 11
+		switch 11 - r {
+		case 11:
+			return a.at(10) == 0
+		case 10:
+			return a.at(10) == 0 && (a.at(2) == 8 || a.at(2) == 9)
+		default:
+			return 11-r == a.at(10)
+		}
+	},
+	"26": func(a *account) bool {
+		d := *a
+		if d[0] == 0 && d[1] == 0 {
+			d = d.shift(2)
+		}
+		return d.mod11(7, 1, w27, true, 8)
+	},
+	"27": method27,
+	"28": method28,
+	"29": method29,
+	"30": func(a *account) bool { return a.mod10(9, 1, []int{2, 1, 2, 1, 0, 0, 0, 0, 2}, false, 10) },
+	"31": func(a *account) bool {
+		r := a.weightedSum(9, 1, []int{9, 8, 7, 6, 5, 4, 3, 2, 1}, false) % 11
+		return r != 10 && r == a.at(10)
+	},
+	"32": method32,
+	"33": method33,
+	"34": func(a *account) bool { return a.mod11(7, 1, []int{2, 4, 8, 5, 10, 9, 7}, true, 8) },
+	"35": func(a *account) bool {
+		r := a.weightedSum(9, 1, w210, false) % 11
+		if r == 10 {
+			return a.at(9) == a.at(10)
+		}
+		return r == a.at(10)
+	},
+	"36": func(a *account) bool { return a.mod11(9, 6, w248510[:4], true, 10) },
+	"37": func(a *account) bool { return a.mod11(9, 5, w248510[:5], true, 10) },
+	"38": func(a *account) bool { return a.mod11(9, 4, w248510[:6], true, 10) },
+	"39": func(a *account) bool { return a.mod11(9, 3, w248510[:7], true, 10) },
+	"40": func(a *account) bool { return a.mod11(9, 1, w248510, true, 10) },
+	"41": func(a *account) bool {
+		if a.at(4) == 9 {
+			return a.mod10(9, 4, w21, true, 10)
+		}
+		return method00(a)
+	},
+	"42": func(a *account) bool { return a.mod11(9, 2, w29, true, 10) },
+	"43": func(a *account) bool { return a.mod10(9, 1, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, false, 10) },
+	"44": func(a *account) bool { return a.mod11(9, 5, w248510[:5], true, 10) },
+	"45": func(a *account) bool { return a.at(1) == 0 || a.at(5) == 1 || method00(a) },
+	"46": func(a *account) bool { return a.mod11(7, 3, w27[:5], true, 8) },
+	"47": func(a *account) bool { return a.mod11(8, 4, w27[:5], true, 9) },
+	"48": func(a *account) bool { return a.mod11(8, 3, w27, true, 9) },
+	"49": func(a *account) bool { return method00(a) || method01(a) },
+	"50": func(a *account) bool {
+		if a.mod11(6, 1, w27, true, 7) {
+			return true
+		}
+		if a.at(1) != 0 || a.at(2) != 0 || a.at(3) != 0 {
+			return false
+		}
+		s := a.shift(3)
+		return s.mod11(6, 1, w27, true, 7)
+	},
+	"54": func(a *account) bool {
+		if a.at(1) != 4 || a.at(2) != 9 {
+			return false
+		}
+		r := a.weightedSum(9, 3, []int{2, 3, 4, 5, 6, 7, 2}, false) % 11
+		return r > 1 && 11-r == a.at(10)
+	},
+	"51": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return method32(a) || method33(a) || a.mod10(9, 4, w21, true, 10) || a.modN(7, 9, 5, w27[:5], false, 10)
+	},
+	"55": func(a *account) bool { return a.mod11(9, 1, []int{2, 3, 4, 5, 6, 7, 8, 7, 8}, true, 10) },
+	"56": func(a *account) bool {
+		r := a.weightedSum(9, 1, w27, false) % 11
+		switch 11 - r {
+		case 10:
+			return a.at(1) == 9 && a.at(10) == 7
+		case 11:
+			return a.at(1) == 9 && a.at(10) == 8
+		default:
+			return 11-r == a.at(10)
+		}
+	},
+	"57": func(a *account) bool {
+		switch p := a.at(1)*10 + a.at(2); p {
+		case 0:
+			return false
+		case 40, 50, 91, 99:
+			return true
+		case 51, 55, 61, 64, 65, 66, 70, 73, 75, 76, 77, 78, 79, 80, 81, 82, 88, 94, 95:
+			if v := a.value() / 10000; v == 777777 || v == 888888 {
+				return true
+			}
+			return a.mod10(9, 1, []int{1, 2}, true, 10)
+		default:
+			if p <= 31 {
+				month := a.at(3)*10 + a.at(4)
+				return month >= 1 && month <= 12 && a.at(7) < 5 || a.value() == 185125434
+			}
+			return a.mod10(10, 1, []int{1, 2, 1, 2, 1, 2, 1, 0, 2, 1}, true, 3)
+		}
+	},
+	"58": method58,
+	"59": func(a *account) bool { return a.value() < 100000000 || method00(a) },
+	"60": func(a *account) bool { return a.mod10(9, 3, w21, true, 10) },
+	"61": func(a *account) bool {
+		sum := a.weightedSum(7, 1, w21, true)
+		if a.at(9) == 8 {
+			sum += a.weightedSum(10, 9, w21, true)
+		}
+		return (10-sum%10)%10 == a.at(8)
+	},
+	"62": func(a *account) bool { return a.mod10(7, 3, w21, true, 8) },
+	"63": method63,
+	"64": func(a *account) bool { return a.mod11(6, 1, []int{2, 4, 8, 5, 10, 9}, true, 7) },
+	"66": func(a *account) bool {
+		if a.at(1) != 0 {
+			return false
+		}
+		if a.at(2) == 9 {
+			return true
+		}
+		switch r := a.weightedSum(9, 2, []int{2, 3, 4, 5, 6, 0, 0, 7}, false) % 11; r {
+		case 0:
+			return a.at(10) == 1
+		case 1:
+			return a.at(10) == 0
+		default:
+			return 11-r == a.at(10)
+		}
+	},
+	"65": func(a *account) bool {
+		sum := a.weightedSum(7, 1, w21, true)
+		if a.at(9) == 9 {
+			sum += a.weightedSum(10, 9, w21, true)
+		}
+		return (10-sum%10)%10 == a.at(8)
+	},
+	"67": func(a *account) bool { return a.mod10(7, 1, w21, true, 8) },
+	"69": func(a *account) bool {
+		v := a.value()
+		if v >= 9300000000 && v <= 9399999999 {
+			return true
+		}
+		if (v < 9700000000 || v > 9799999999) && method28(a) {
+			return true
+		}
+		return method29(a)
+	},
+	"68": method68,
+	"70": func(a *account) bool {
+		if a.at(4) == 5 || (a.at(4) == 6 && a.at(5) == 9) {
+			return method32(a)
+		}
+		return method06(a)
+	},
+	"71": func(a *account) bool {
+		switch r := a.weightedSum(7, 2, []int{1, 2, 3, 4, 5, 6}, false) % 11; r {
+		case 0:
+			return a.at(10) == 0
+		case 1:
+			return a.at(10) == 1
+		default:
+			return 11-r == a.at(10)
+		}
+	},
+	"72": func(a *account) bool { return a.mod10(9, 4, w21, true, 10) },
+	"77": func(a *account) bool {
+		return a.weightedSum(10, 6, []int{1, 2, 3, 4, 5}, false)%11 == 0 ||
+			a.weightedSum(10, 6, []int{5, 4, 3, 4, 5}, false)%11 == 0
+	},
+	"76": func(a *account) bool {
+		check := func(a *account) bool {
+			switch a.at(1) {
+			case 0, 4, 6, 7, 8, 9:
+			default:
+				return false
+			}
+			r := a.weightedSum(7, 2, w27, false) % 11
+			return r != 10 && r == a.at(8)
+		}
+		if check(a) {
+			return true
+		}
+		s := a.shift(2)
+		return a.at(1) == 0 && a.at(2) == 0 && check(&s)
+	},
+	"75": method75,
+	"74": func(a *account) bool {
+		if method00(a) {
+			return true
+		}
+		if v := a.value(); v < 100000 || v > 999999 {
+			return false
+		}
+		return (5-a.weightedSum(9, 1, w21, true)%5)%5 == a.at(10)
+	},
+	"73": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return a.mod10(9, 4, w21, true, 10) || a.mod10(9, 5, w21, true, 10) || a.modN(7, 9, 5, w21, true, 10)
+	},
+	"78": func(a *account) bool {
+		if v := a.value(); v >= 10000000 && v < 100000000 {
+			return true
+		}
+		return method00(a)
+	},
+	"79": func(a *account) bool {
+		switch a.at(1) {
+		case 0:
+			return false
+		case 1, 2, 9:
+			return a.mod10(8, 1, w21, true, 9)
+		default:
+			return method00(a)
+		}
+	},
+	"81": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return method32(a)
+	},
+	"80": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return a.mod10(9, 5, w21, true, 10) || a.modN(7, 9, 5, w21, true, 10)
+	},
+	"82": func(a *account) bool {
+		if a.at(3) == 9 && a.at(4) == 9 {
+			return method10(a)
+		}
+		return method33(a)
+	},
+	"87": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return method87A(a) || method33(a) || a.modN(7, 9, 5, w27[:5], false, 10)
+	},
+	"86": func(a *account) bool {
+		if a.at(3) == 9 {
+			return a.mod11(9, 3, w29[:7], true, 10)
+		}
+		return a.mod10(9, 4, w21, true, 10) || method32(a)
+	},
+	"85": method83,
+	"84": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return method33(a) || a.modN(7, 9, 5, w27[:5], false, 10) || a.mod10(9, 5, w21, false, 10)
+	},
+	"83": method83,
+	"88": func(a *account) bool {
+		if a.at(3) == 9 {
+			return a.mod11(9, 3, w29[:7], true, 10)
+		}
+		return method32(a)
+	},
+	"91": func(a *account) bool {
+		return a.mod11(6, 1, w27, true, 7) ||
+			a.mod11(6, 1, []int{7, 6, 5, 4, 3, 2}, true, 7) ||
+			a.mod11(10, 1, []int{2, 3, 4, 0, 5, 6, 7, 8, 9, 10}, true, 7) ||
+			a.mod11(6, 1, w248510[:6], true, 7)
+	},
+	"90": func(a *account) bool {
+		if a.at(3) == 9 {
+			return a.mod11(9, 3, w29[:7], true, 10)
+		}
+		return method32(a) || method33(a) || a.modN(7, 9, 5, w27[:5], false, 10) ||
+			a.modN(9, 9, 5, w27[:5], false, 10) || a.mod10(9, 5, w21, false, 10) ||
+			a.modN(7, 9, 4, w21, true, 10)
+	},
+	"89": func(a *account) bool { return a.value() < 10000000 || method10(a) },
+	"92": func(a *account) bool { return a.mod10(9, 4, []int{3, 7, 1}, false, 10) },
+	"93": method93,
+	"94": func(a *account) bool { return a.mod10(9, 1, []int{1, 2}, true, 10) },
+	"95": method95,
+	"96": func(a *account) bool {
+		if v := a.value(); v >= 1300000 && v <= 99399999 {
+			return true
+		}
+		return method19(a) || method00(a)
+	},
+	"97": func(a *account) bool {
+		return a.value()/10%11%10 == int64(a.at(10))
+	},
+	"98": func(a *account) bool {
+		return a.mod10(9, 3, []int{3, 1, 7}, false, 10) || method32(a)
+	},
+	"99": func(a *account) bool {
+		if v := a.value(); v >= 396000000 && v <= 499999999 {
+			return true
+		}
+		return method06(a)
+	},
+	"A0": func(a *account) bool {
+		return a.value() < 1000 || a.mod11(9, 5, w248510[:5], true, 10)
+	},
+	"A1": func(a *account) bool {
+		if v := a.value(); (v < 10000000 || v > 99999999) && v < 1000000000 {
+			return false
+		}
+		return a.mod10(9, 1, []int{2, 1, 2, 1, 2, 1, 2, 0, 0}, true, 10)
+	},
+	"A2": func(a *account) bool { return method00(a) || method04(a) },
+	"A3": func(a *account) bool { return method00(a) || method10(a) },
+	"A6": func(a *account) bool {
+		if a.at(2) == 8 {
+			return method00(a)
+		}
+		return method01(a)
+	},
+	"A5": func(a *account) bool { return method00(a) || a.at(1) != 9 && method10(a) },
+	"A4": func(a *account) bool {
+		if a.at(3) != 9 || a.at(4) != 9 {
+			if a.mod11(9, 4, w27, true, 10) || a.modN(7, 9, 4, w27, false, 10) {
+				return true
+			}
+		} else if a.mod11(9, 5, w27[:5], true, 10) {
+			return true
+		}
+		return method93(a)
+	},
+	"A7": func(a *account) bool { return method00(a) || method03(a) },
+	"A8": func(a *account) bool {
+		if a.at(3) == 9 {
+			return method51Ledger(a)
+		}
+		return method32(a) || a.mod10(9, 4, w21, true, 10)
+	},
+	"A9": func(a *account) bool { return method01(a) || method06(a) },
+	"E3": func(a *account) bool { return method00(a) || method21(a) },
+	"E2": func(a *account) bool { return a.at(1) <= 5 && a.prefixedMod10("438320", 1) },
+	"E1": func(a *account) bool {
+		sum := 0
+		for i, pos := 0, 9; pos >= 1; i, pos = i+1, pos-1 {
+			sum += (a.at(pos) + '0') * []int{1, 2, 3, 4, 5, 6, 11, 10, 9}[i]
+		}
+		r := sum % 11
+		return r != 10 && r == a.at(10)
+	},
+	"E0": func(a *account) bool { return (10-(a.weightedSum(9, 1, w21, true)+7)%10)%10 == a.at(10) },
+	"D9": func(a *account) bool { return method00(a) || method10(a) || method18(a) },
+	"D8": func(a *account) bool {
+		switch v := a.value(); {
+		case v >= 1000000000:
+			return method00(a)
+		case v >= 10000000:
+			return true
+		}
+		return false
+	},
+	"D7": func(a *account) bool { return a.weightedSum(9, 1, w21, true)%10 == a.at(10) },
+	"D6": func(a *account) bool { return method07(a) || method03(a) || method00(a) },
+	"D5": func(a *account) bool {
+		if a.at(3) == 9 && a.at(4) == 9 {
+			return a.mod11(9, 3, w29[:7], true, 10)
+		}
+		return a.mod11(9, 4, w27, true, 10) || a.modN(7, 9, 4, w27, false, 10) || a.modN(10, 9, 4, w27, false, 10)
+	},
+	"D4": func(a *account) bool { return a.at(1) != 0 && a.prefixedMod10("428259", 1) },
+	"D3": func(a *account) bool { return method00(a) || method27(a) },
+	"D2": func(a *account) bool { return method95(a) || method00(a) || method68(a) },
+	"D1": func(a *account) bool { return a.at(1) != 8 && a.prefixedMod10("5499570", 1) },
+	"D0": func(a *account) bool { return a.at(1) == 5 && a.at(2) == 7 || method20(a) },
+	"C9": func(a *account) bool { return method00(a) || method07(a) },
+	"C8": func(a *account) bool { return method00(a) || method04(a) || method07(a) },
+	"C7": func(a *account) bool { return method63(a) || method06(a) },
+	"C6": func(a *account) bool {
+		prefixes := []string{"4451970", "4451981", "4451992", "4451993", "4344992", "4344990", "4344991", "5499570", "4451994", "5499579"}
+		return a.prefixedMod10(prefixes[a.at(1)], 2)
+	},
+	"C5": func(a *account) bool {
+		switch v := a.value(); {
+		case v >= 100000 && v <= 999999:
+			return a.at(5) >= 1 && a.at(5) <= 8 && method75(a)
+		case v >= 10000000 && v <= 99999999:
+			return a.at(3) >= 3 && a.at(3) <= 5
+		case v >= 100000000 && v <= 999999999:
+			return a.at(2) >= 1 && a.at(2) <= 8 && method75(a)
+		case v >= 1000000000:
+			switch a.at(1) {
+			case 1, 4, 5, 6, 9:
+				return method29(a)
+			case 3:
+				return method00(a)
+			case 7:
+				return a.at(2) == 0
+			case 8:
+				return a.at(2) == 5
+			}
+		}
+		return false
+	},
+	"C4": func(a *account) bool {
+		if a.at(1) == 9 {
+			return method58(a)
+		}
+		return method15(a)
+	},
+	"C3": func(a *account) bool {
+		if a.at(1) == 9 {
+			return method58(a)
+		}
+		return method00(a)
+	},
+	"C2": func(a *account) bool { return method22(a) || method00(a) },
+	"C1": func(a *account) bool {
+		if a.at(1) != 5 {
+			return method17(a)
+		}
+		r := (a.weightedSum(9, 1, []int{1, 2}, true) - 1) % 11
+		return (10-r)%10 == a.at(10)
+	},
+	"B9": func(a *account) bool {
+		var sum int
+		switch {
+		case a.at(1) == 0 && a.at(2) == 0 && a.at(3) != 0:
+			for i, pos := 0, 9; pos >= 3; i, pos = i+1, pos-1 {
+				w := []int{1, 3, 2}[i%3]
+				sum += (a.at(pos)*w + w) % 11
+			}
+			sum %= 10
+		case a.at(1) == 0 && a.at(2) == 0 && a.at(3) == 0 && a.at(4) != 0:
+			sum = a.weightedSum(9, 4, []int{1, 2, 3, 4, 5, 6}, false) % 11
+		default:
+			return false
+		}
+		return sum == a.at(10) || (sum+5)%10 == a.at(10)
+	},
+	"B8": func(a *account) bool {
+		if method20(a) || method29(a) {
+			return true
+		}
+		v := a.value()
+		return v >= 5100000000 && v <= 5999999999 || v >= 9010000000 && v <= 9109999999
+	},
+	"B7": func(a *account) bool {
+		if v := a.value(); v >= 1000000 && v <= 5999999 || v >= 700000000 && v <= 899999999 {
+			return method01(a)
+		}
+		return true
+	},
+	"B5": func(a *account) bool {
+		if method05(a) {
+			return true
+		}
+		return a.at(1) != 8 && a.at(1) != 9 && method00(a)
+	},
+	"B4": func(a *account) bool {
+		if a.at(1) == 9 {
+			return method00(a)
+		}
+		return a.mod11(9, 1, w210, false, 10)
+	},
+	"B3": func(a *account) bool {
+		if a.at(1) == 9 {
+			return method06(a)
+		}
+		return method32(a)
+	},
+	"B2": func(a *account) bool {
+		if a.at(1) >= 8 {
+			return method00(a)
+		}
+		return method02(a)
+	},
+	"B1": func(a *account) bool { return method05(a) || method01(a) || method00(a) },
+	"B0": func(a *account) bool {
+		if a.value() < 1000000000 || a.at(1) == 8 {
+			return false
+		}
+		switch a.at(8) {
+		case 1, 2, 3, 6:
+			return true
+		}
+		return method06(a)
+	},
+	"E4": func(a *account) bool { return method02(a) || method00(a) },
+}
+
+// bankAccountCheckMethods holds the methods that also need the bank
+// code (BLZ).
+var bankAccountCheckMethods = map[string]func(a *account, bankCode string) bool{
+	"52": method52,
+	"53": method53,
+	"B6": func(a *account, bankCode string) bool {
+		if a.at(1) != 0 || a.value()/100000 >= 2691 && a.value()/100000 <= 2699 {
+			return method20(a)
+		}
+		return method53(a, bankCode)
+	},
+	"C0": func(a *account, bankCode string) bool {
+		if a.at(1) == 0 && a.at(2) == 0 && a.at(3) != 0 && method52(a, bankCode) {
+			return true
+		}
+		return method20(a)
+	},
+}
+
+// AccountCheckMethods lists the check methods that CheckGermanAccount
+// implements.
+func AccountCheckMethods() []string {
+	out := make([]string, 0, len(accountCheckMethods)+len(bankAccountCheckMethods))
+	for m := range accountCheckMethods {
+		out = append(out, m)
+	}
+	for m := range bankAccountCheckMethods {
+		out = append(out, m)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func accountCheckSupported(method string) bool {
+	_, ok := accountCheckMethods[method]
+	_, bankOK := bankAccountCheckMethods[method]
+	return ok || bankOK
+}
+
+// CheckGermanAccount runs check method on a German account number of up
+// to ten digits held at the bank with the eight-digit bankCode, which
+// methods 52, 53, B6 and C0 need. It reports whether the method is
+// implemented and, if so, whether the check digit is valid. An
+// unimplemented method is never reported as valid.
+func CheckGermanAccount(method, bankCode, number string) (valid, supported bool) {
+	if !accountCheckSupported(method) {
+		return false, false
+	}
+	if len(number) == 0 || len(number) > 10 || !isDigits(number) {
+		return false, true
+	}
+	var a account
+	off := 10 - len(number)
+	for i := range number {
+		a[off+i] = int(number[i] - '0')
+	}
+	if a.value() == 0 {
+		return false, true
+	}
+	if check, ok := accountCheckMethods[method]; ok {
+		return check(&a), true
+	}
+	return bankAccountCheckMethods[method](&a, bankCode), true
+}
+
+// checkAccount runs the account check of the bank behind a German IBAN
+// when the deep check is on and the directory knows the bank's method.
+func checkAccount(iban string) error {
+	if !accountCheck.Load() || iban[:2] != "DE" {
+		return nil
+	}
+	e, ok := bicDirectory.Load().Lookup(iban)
+	if !ok || e.CheckMethod == "" {
+		return nil
+	}
+	if valid, supported := CheckGermanAccount(e.CheckMethod, iban[4:12], iban[12:]); supported && !valid {
+		return fieldErrorf("iban", CodeInvalidCheckDigit, "iban account check digit invalid")
+	}
+	return nil
+}
+
+// accountCheckWarnings reports a German IBAN whose bank uses a check
+// method CheckGermanAccount does not implement, so the account number
+// went unchecked although the deep check is on.
+func accountCheckWarnings(iban string, warnings []Warning) []Warning {
+	if !accountCheck.Load() || !strings.HasPrefix(iban, "DE") {
+		return warnings
+	}
+	e, ok := bicDirectory.Load().Lookup(iban)
+	if !ok || e.CheckMethod == "" {
+		return warnings
+	}
+	if accountCheckSupported(e.CheckMethod) {
+		return warnings
+	}
+	return append(warnings, Warning{
+		Field:   "iban",
+		Code:    "account_check_unsupported",
+		Message: fmt.Sprintf("iban account not checked: check method %s unsupported", e.CheckMethod),
+	})
+}
@@ -0,0 +1,212 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCheckGermanAccount(t *testing.T) {
+	cases := []struct {
+		method, number string
+		valid          bool
+	}{
+		// Accounts from published IBANs of banks using the method.
+		{"00", "0000202051", true},
+		{"00", "1015871393", true},
+		{"00", "9290701", true},
+		{"00", "9290702", false},
+		{"06", "1234567892", true},
+		{"06", "1234567893", false},
+		{"13", "0532013000", true},
+		{"13", "0532013001", true}, // sub-account digits are not checked
+		{"13", "0532113000", false},
+		{"17", "0648489890", true},
+		{"17", "0137075030", true},
+		{"17", "0446786040", true},
+		{"17", "0648489990", false},
+		{"24", "0006820101", true},
+		{"24", "138301", true},
+		{"24", "0006820102", false},
+		{"09", "1234567890", true},
+		{"00", "0", false},
+		{"00", "12345678901", false},
+		{"00", "12a4", false},
+	}
+	for _, tc := range cases {
+		valid, supported := CheckGermanAccount(tc.method, "", tc.number)
+		if !supported || valid != tc.valid {
+			t.Fatalf("method %s account %s: got valid=%v supported=%v, want %v", tc.method, tc.number, valid, supported, tc.valid)
+		}
+	}
+	if valid, supported := CheckGermanAccount("12", "", "1234567890"); valid || supported {
+		t.Fatalf("unimplemented method must not report valid, got valid=%v supported=%v", valid, supported)
+	}
+}
+
+// TestCheckGermanAccount_BundesbankTestNumbers runs the test account
+// numbers published with the Bundesbank method descriptions.
+func TestCheckGermanAccount_BundesbankTestNumbers(t *testing.T) {
+	cases := []struct {
+		method, bankCode string
+		valid, invalid   []string
+	}{
+		{"35", "", []string{"0000108443", "0000107451", "0000102921", "0000102349", "0000101709", "0000101599"}, []string{"0000108444", "0000107452", "0000102922", "0000102340", "0000101708", "0000101598"}},
+		{"51", "", []string{"0001156071", "0001156136", "0000156078", "0000156071", "0199100002", "0099100010", "2599100002", "0199100004", "2599100003", "3199204090"}, []string{"0099345678", "0099100110", "0199100040"}},
+		{"52", "13051172", []string{"43001500", "48726458"}, []string{"29837521"}},
+		{"53", "80053762", []string{"487310018"}, nil},
+		{"54", "", []string{"4964137395", "4900010987"}, []string{"4964137396", "4900010986", "1234567890"}},
+		{"57", "", []string{"7500021766", "9400001734", "7800028282", "8100244186", "0185125434"}, nil},
+		{"61", "", []string{"2063099200", "0260760481"}, nil},
+		{"65", "", []string{"1234567400", "1234567590"}, nil},
+		{"66", "", []string{"100150502", "100154508", "101154508", "100154516", "101154516", "983393104"}, nil},
+		{"68", "", []string{"8889654328", "987654324", "987654328", "400000000", "499999999"}, nil},
+		{"69", "", []string{"9721134869", "1234567900", "1234567006"}, nil},
+		{"73", "", []string{"0003503398", "0001340967", "0003503391", "0001340968", "0003503392", "0001340966", "0199100002", "0099100010", "2599100002", "0199100004", "2599100003", "3199204090"}, []string{"0003503399", "0001340969"}},
+		{"74", "", []string{"1016", "26260", "242243", "242248", "18002113", "1821200043"}, []string{"1011", "26265", "18002118", "6160000024"}},
+		{"76", "", []string{"0006543200", "9012345600", "7876543100"}, nil},
+		{"77", "", []string{"10338", "13844", "65354", "69258"}, nil},
+		{"80", "", []string{"340968", "340966", "0199100002"}, nil},
+		{"81", "", []string{"0646440", "1359100", "0199100002"}, nil},
+		{"83", "", []string{"0001156071", "0001156136", "0000156078", "0000156071", "0099100002"}, nil},
+		{"84", "", []string{"240699", "350982", "461059", "240692", "350985", "0199100002", "0199100004"}, nil},
+		{"85", "", []string{"0001156071", "0001156136", "0000156078", "0000156071", "0099100002"}, nil},
+		{"86", "", []string{"340968", "1001171", "1009588", "123897", "340960"}, nil},
+		{"87", "", []string{"0000000406", "0000051768", "0010701590", "0010720185", "0000100005", "0000393814", "0000950360"}, nil},
+		{"89", "", []string{"1098506", "32028008", "218433000"}, nil},
+		{"90", "", []string{"0001975641", "0001988654", "0000654321", "0000824491", "0099100002"}, nil},
+		{"91", "", []string{"2974118000", "5281741000", "9952810000", "2974117000", "5281770000", "9952812000", "8840019000", "8840050000", "8840087000", "8840045000", "8840012000", "8840055000", "8840080000"}, []string{"8840011000", "8840014000"}},
+		{"93", "", []string{"6714790000", "0000671479", "1277830000", "0000127783"}, nil},
+		{"A1", "", []string{"0010030005", "0010030997", "1010030054"}, []string{"0110030005", "0010030998", "0000030005"}},
+		{"A4", "", []string{"0004711173", "0007093330", "0004711172", "0007093335", "1199503010", "8499421235", "0000862342", "8997710000", "0664040000", "0000905844", "5030101099", "0001123458", "1299503117"}, []string{"0000399443", "0000553313"}},
+		{"A5", "", []string{"9941510001", "9961230019", "9380027210", "9932290910", "0000251437", "0007948344", "0000159590", "0000051640"}, []string{"9941510002", "0000251438"}},
+		{"A6", "", []string{"800048548", "0855000014", "17", "55300030", "150178033", "600003555", "900291823"}, nil},
+		{"A8", "", []string{"7436661", "7436670", "1359100", "7436660", "7436678", "0003503398", "0001340967", "0199100002", "0099100010", "2599100002", "0199100004", "2599100003", "3199204090"}, []string{"7436666", "7436677", "0003503391", "0001340968"}},
+		{"B0", "", []string{"1197423162", "1000000606"}, nil},
+		{"B1", "", []string{"1434253150", "2746315471", "7414398260", "8347251693"}, nil},
+		{"B2", "", []string{"0020012357", "0080012345", "0926801910", "1002345674"}, []string{"0020012399", "0080012347"}},
+		{"B3", "", []string{"1000000060", "0000000140", "0000000019", "1002798417", "8409915001", "9635000101", "9730200100"}, nil},
+		{"B4", "", []string{"9941510001", "9961230019", "9380027210", "9932290910", "0000251437", "0007948344", "0000051640"}, nil},
+		{"B5", "", []string{"0159006955", "2000123451", "1151043216", "9000939033", "0123456782", "0130098767", "1045000252"}, []string{"0159006950"}},
+		{"B6", "", []string{"9110000000", "0269876545"}, []string{"9111000000", "0269456780"}},
+		{"B6", "80053762", []string{"487310018"}, []string{"467310018"}},
+		{"B7", "", []string{"0700001529", "0730000019", "0001001008", "0001057887", "0001007222", "0810011825", "0800107653", "0005922372"}, []string{"0001057886", "0003815570", "0005620516", "0740912243", "0893524479"}},
+		{"B8", "", []string{"0734192657", "6932875274", "3145863029", "2938692523", "5011654366"}, []string{"0132572975", "9000412340", "9310305011"}},
+		{"B9", "", []string{"87920187", "41203755", "81069577", "61287958", "58467232", "7125633", "1253657", "4353631"}, []string{"88034023", "43025432", "86521362", "61256523", "54352684", "2356412", "5435886", "9435414"}},
+		{"C0", "", []string{"0082335729", "0734192657", "6932875274"}, []string{"0132572975", "3038752371"}},
+		{"C0", "13051172", []string{"43001500", "48726458"}, []string{"29837521"}},
+		{"C1", "", []string{"0446786040", "0478046940", "0701625830", "0701625840", "0882095630", "5432112349", "5543223456", "5654334563", "5765445670", "5876556788"}, []string{"0446786240", "0478046340", "0701625730", "5432112341"}},
+		{"C2", "", []string{"2394871426", "4218461950", "7352569148", "5127485166", "8738142564"}, []string{"0328705282", "9024675131"}},
+		{"C3", "", []string{"9294182", "4431276", "19919", "9000420530", "9000010006", "9000577650"}, []string{"17002", "123451", "122448", "9000734028", "9000733227", "9000731120"}},
+		{"C4", "", []string{"0000000019", "0000292932", "0000094455", "9000420530", "9000010006", "9000577650"}, []string{"0000000017", "0000292933", "0000094459", "9000726558", "9001733457", "9000732000"}},
+		{"C5", "", []string{"0000301168", "0000302554", "0300020050", "0300566000", "1000061378", "1000061412", "4450164064", "4863476104", "5000000028", "5000000391", "6450008149", "6800001016", "9000100012", "9000210017", "3060188103", "3070402023", "0030000000", "7000000000", "8500000000"}, []string{"0000302589", "0000507336", "0302555000", "0302048000", "1000061457", "1000061498", "4864446015", "4865038012", "5000001028", "5000001075", "6450008150", "6542812818", "9000110012", "9000300310", "3081000783", "3081308871"}},
+		{"C6", "", []string{"0000065516", "0203178249", "1031405209", "1082012201", "2003455189", "2004001016", "3110150986", "3068459207", "5035105948", "5286102149", "4012660028", "4100235626", "6028426119", "6861001755", "7008199027", "7002000023", "8526080015", "8711072264", "9000430223", "9000781153"}, []string{"0525111212", "0091423614", "1082311275", "1000118821", "2004306518", "2016001206", "3462816371", "3622548632", "4232300158", "4000456126", "5002684526", "5564123850", "6295473774", "6640806317", "7000062022", "7006003027", "8348300002", "8654216984", "9000641509", "9000260986"}},
+		{"C7", "", []string{"3500022", "38150900", "600103660", "39101181", "94012341", "5073321010"}, []string{"1234517892", "987614325"}},
+		{"C8", "", []string{"3456789019", "5678901231", "6789012348", "3456789012", "0022007130", "0123456789", "0552071285"}, nil},
+		{"C9", "", []string{"3456789019", "5678901231", "0123456789"}, []string{"3456789012", "1234567890", "9012345678"}},
+		{"D0", "", []string{"6100272324", "6100273479", "5700000000"}, []string{"6100272885", "6100273377", "6100274012"}},
+		{"D1", "", []string{"0082012203", "1452683581", "2129642505", "3002000027", "4230001407", "5000065514", "6001526215", "7126502149", "9000430223"}, []string{"0000260986", "1062813622", "2256412314", "3012084101", "4006003027", "5814500990", "6128462594", "7000062035", "8003306026", "9000641509"}},
+		{"D2", "", []string{"189912137", "235308215", "4455667784", "1234567897", "51181008", "71214205"}, []string{"6414241", "179751314"}},
+		{"D3", "", []string{"1600169591", "1600189151", "1800084079", "6019937007", "6021354007", "6030642006"}, []string{"1600166307", "6025017009", "6028267003", "6019835001"}},
+		{"D4", "", []string{"1112048219", "2024601814", "3000005012", "4143406984", "5926485111", "6286304975", "7900256617", "8102228628", "9002364588"}, []string{"0359432843", "1000062023", "2204271250", "3051681017", "4000123456", "5212744564", "6286420010", "7859103459", "8003306026", "9916524534"}},
+		{"D5", "", []string{"5999718138", "1799222116", "0099632004", "0004711173", "0007093330", "0000127787", "0004711172", "0007093335", "0000100062"}, []string{"3299632008", "1999204293", "0399242139"}},
+		{"D6", "", []string{"3409", "585327", "1650513"}, nil},
+		{"D7", "", []string{"0500018205", "0230103715", "0301000434", "0330035104", "0420001202", "0134637709", "0201005939", "0602006999"}, []string{"0501006102", "0231307867", "0301005331", "0330034104", "0420001302", "0134637201", "0601006977"}},
+		{"D8", "", []string{"1403414848", "6800000439", "6899999954", "0010000000", "0099999999"}, []string{"0000260986", "0009999999"}},
+		{"D9", "", []string{"1234567897", "0123456782", "9876543210", "1234567890"}, nil},
+		{"E0", "", []string{"1234568013", "1534568010", "2610015", "8741013011"}, []string{"1234769013", "2710014", "9741015011"}},
+		{"E1", "", []string{"0134211909", "0100041104", "0100054106", "0200025107"}, []string{"0150013107", "0200035101", "0081313890", "4268550840", "0987402008"}},
+		{"E2", "", []string{"0003831745", "0051330335", "4130025007"}, []string{"6330053102", "7330056105", "8930123107", "9330071103"}},
+		{"E3", "", []string{"9290701", "539290858", "1501824", "1501832"}, nil},
+	}
+	for _, tc := range cases {
+		check := func(number string, want bool) {
+			valid, supported := CheckGermanAccount(tc.method, tc.bankCode, number)
+			if !supported || valid != want {
+				t.Errorf("method %s account %s: got valid=%v supported=%v, want %v", tc.method, number, valid, supported, want)
+			}
+		}
+		for _, n := range tc.valid {
+			check(n, true)
+		}
+		for _, n := range tc.invalid {
+			check(n, false)
+		}
+	}
+}
+
+func TestCleanAndValidate_AccountCheckUnsupportedMethod(t *testing.T) {
+	SetBICDirectory(NewBICDirectory([]BankEntry{{Country: "DE", BankCode: "50010517", BIC: "INGDDEFFXXX", CheckMethod: "12"}}))
+	defer SetBICDirectory(nil)
+	SetAccountCheck(true)
+	defer SetAccountCheck(false)
+
+	cleaned, err := CleanAndValidate(Input{Name: "Example GmbH", IBAN: "DE12500105170648489890", BIC: "INGDDEFFXXX", Amount: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Warning{Field: "iban", Code: "account_check_unsupported", Message: "iban account not checked: check method 12 unsupported"}
+	if len(cleaned.Warnings) != 1 || cleaned.Warnings[0] != want {
+		t.Fatalf("expected unsupported method warning, got %+v", cleaned.Warnings)
+	}
+}
+
+// TestAccountCheckMethodsCatchTypos checks that no implemented method
+// (other than 09, "no check") degenerates into accepting every typo. The
+// second base lies in the checked range of methods that skip the first.
+func TestAccountCheckMethodsCatchTypos(t *testing.T) {
+	bases := []string{"5317264093", "0731726409"}
+	for _, m := range AccountCheckMethods() {
+		if m == "09" {
+			continue
+		}
+		rejected := 0
+		for _, base := range bases {
+			for pos := 0; pos < len(base); pos++ {
+				for d := byte('0'); d <= '9'; d++ {
+					n := base[:pos] + string(d) + base[pos+1:]
+					if valid, _ := CheckGermanAccount(m, "", n); !valid {
+						rejected++
+					}
+				}
+			}
+		}
+		if rejected == 0 {
+			t.Fatalf("method %s accepts every single-digit change", m)
+		}
+	}
+}
+
+func TestCheckIBAN_AccountCheck(t *testing.T) {
+	SetBICDirectory(testBICDirectory(t))
+	defer SetBICDirectory(nil)
+
+	// DE12500105170648489890 with a mistyped account digit and recomputed
+	// IBAN check digits: mod-97 passes, method 17 does not.
+	bban := "500105170648489990"
+	mod, _ := mod97(bban + "DE00")
+	typo := fmt.Sprintf("DE%02d%s", 98-mod, bban)
+	if err := CheckIBAN(typo); err != nil {
+		t.Fatalf("deep check is off by default: %v", err)
+	}
+
+	SetAccountCheck(true)
+	defer SetAccountCheck(false)
+	if err := CheckIBAN(typo); err == nil || err.Error() != "iban account check digit invalid" {
+		t.Fatalf("expected account check error, got %v", err)
+	}
+	// Banks without a BIC in the directory are still checked.
+	bban = "100101110532113000"
+	mod, _ = mod97(bban + "DE00")
+	if err := CheckIBAN(fmt.Sprintf("DE%02d%s", 98-mod, bban)); err == nil {
+		t.Fatalf("expected account check error for a bank without BIC")
+	}
+	for _, iban := range []string{
+		"DE12500105170648489890",
+		"DE89370400440532013000", // bank not in the directory
+		"AT611904300234573201",
+	} {
+		if err := CheckIBAN(iban); err != nil {
+			t.Fatalf("%s: %v", iban, err)
+		}
+	}
+}
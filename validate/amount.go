@@ -1,7 +1,6 @@
 package validate
 
 import (
-	"fmt"
 	"regexp"
 	"slices"
 	"strings"
@@ -20,10 +19,29 @@ func SetAmountLenientOCR(enabled bool) {
 	amountLenientOCR.Store(enabled)
 }
 
+// parseAmount parses an amount in the given ISO 4217 currency. The input may
+// carry that currency code before or after the number; other currency
+// markers are rejected. EUR amounts use the full EUR parser.
+func parseAmount(s, amountFormat, currency string) (int64, error) {
+	if currency == "EUR" {
+		return parseAmountEUR(s, amountFormat)
+	}
+	v := strings.TrimSpace(s)
+	if n := len(currency); len(v) > n && strings.EqualFold(v[:n], currency) {
+		v = strings.TrimSpace(v[n:])
+	} else if len(v) > n && strings.EqualFold(v[len(v)-n:], currency) {
+		v = strings.TrimSpace(v[:len(v)-n])
+	}
+	if found := detectCurrency(v); found != "" {
+		return 0, fieldErrorf("amount", CodeUnsupported, "unsupported currency: %s (only %s is allowed)", found, currency)
+	}
+	return parseAmountEUR(v, amountFormat)
+}
+
 func parseAmountEUR(s, amountFormat string) (int64, error) {
 	v := strings.TrimSpace(s)
 	if v == "" {
-		return 0, fmt.Errorf("amount is required")
+		return 0, fieldErrorf("amount", CodeRequired, "amount is required")
 	}
 	format := strings.ToLower(strings.TrimSpace(amountFormat))
 
@@ -41,22 +59,22 @@ func parseAmountEUR(s, amountFormat string) (int64, error) {
 		}
 	case "auto_eur_lenient":
 		if !amountLenientOCR.Load() {
-			return 0, fmt.Errorf("unsupported amount_format")
+			return 0, fieldErrorf("amount_format", CodeUnsupported, "unsupported amount_format")
 		}
 		normalized, currency, err = normalizeAmountInputLenient(v)
 	case "eur_dot", "eur_comma", "eur_grouped_space_comma", "eur_grouped_dot_comma":
 		normalized, currency, err = normalizeAmountByProfile(v, format)
 	default:
-		return 0, fmt.Errorf("unsupported amount_format")
+		return 0, fieldErrorf("amount_format", CodeUnsupported, "unsupported amount_format")
 	}
 	if err != nil {
 		return 0, err
 	}
 	if currency != "" && currency != "EUR" {
-		return 0, fmt.Errorf("unsupported currency: %s (only EUR is allowed)", currency)
+		return 0, fieldErrorf("amount", CodeUnsupported, "unsupported currency: %s (only EUR is allowed)", currency)
 	}
 	if !reAmount.MatchString(normalized) {
-		return 0, fmt.Errorf("invalid amount")
+		return 0, fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 
 	normalized = strings.ReplaceAll(normalized, ",", ".")
@@ -94,37 +112,37 @@ func normalizeAmountByProfile(v, format string) (string, string, error) {
 	switch format {
 	case "eur_dot":
 		if !reAmountEURDot.MatchString(normalized) {
-			return "", "", fmt.Errorf("invalid amount")
+			return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 		}
 		return normalized, currency, nil
 	case "eur_comma":
 		if !reAmountEURComma.MatchString(normalized) {
-			return "", "", fmt.Errorf("invalid amount")
+			return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 		}
 		return normalized, currency, nil
 	case "eur_grouped_space_comma":
 		if strings.Contains(normalized, ".") {
-			return "", "", fmt.Errorf("invalid amount")
+			return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 		}
 		if strings.Contains(normalized, " ") {
 			if !reAmountEURGroupedSpaceComma.MatchString(normalized) {
-				return "", "", fmt.Errorf("invalid amount")
+				return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 			}
 		} else if !reAmountEURComma.MatchString(normalized) {
-			return "", "", fmt.Errorf("invalid amount")
+			return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 		}
 		return strings.ReplaceAll(normalized, " ", ""), currency, nil
 	case "eur_grouped_dot_comma":
 		if strings.Contains(normalized, ".") {
 			if !reAmountEURGroupedDotComma.MatchString(normalized) {
-				return "", "", fmt.Errorf("invalid amount")
+				return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 			}
 		} else if !reAmountEURComma.MatchString(normalized) {
-			return "", "", fmt.Errorf("invalid amount")
+			return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 		}
 		return strings.ReplaceAll(normalized, ".", ""), currency, nil
 	default:
-		return "", "", fmt.Errorf("unsupported amount_format")
+		return "", "", fieldErrorf("amount_format", CodeUnsupported, "unsupported amount_format")
 	}
 }
 
@@ -133,7 +151,7 @@ func normalizeAmountInput(v string) (string, string, error) {
 	hasEUR := strings.Contains(upper, "EUR") || strings.Contains(upper, "EURO") || strings.Contains(v, "€")
 	hasUSD := strings.Contains(upper, "USD") || strings.Contains(v, "$")
 	if hasEUR && hasUSD {
-		return "", "", fmt.Errorf("invalid amount")
+		return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 
 	currency := ""
@@ -153,7 +171,7 @@ func normalizeAmountInput(v string) (string, string, error) {
 	normalized = strings.Join(strings.Fields(normalized), " ")
 
 	if normalized == "" {
-		return "", "", fmt.Errorf("invalid amount")
+		return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 	return normalized, currency, nil
 }
@@ -174,7 +192,7 @@ func normalizeAmountInputLenient(v string) (string, string, error) {
 	}
 	numeric := b.String()
 	if numeric == "" {
-		return "", "", fmt.Errorf("invalid amount")
+		return "", "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 
 	normalized, err := normalizeNumericSeparators(numeric)
@@ -283,7 +301,7 @@ func normalizeNumericSeparators(v string) (string, error) {
 
 	n := out.String()
 	if n == "" || n == "." {
-		return "", fmt.Errorf("invalid amount")
+		return "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 	if strings.HasPrefix(n, ".") {
 		n = "0" + n
@@ -292,18 +310,18 @@ func normalizeNumericSeparators(v string) (string, error) {
 		n = strings.TrimSuffix(n, ".")
 	}
 	if strings.Count(n, ".") > 1 {
-		return "", fmt.Errorf("invalid amount")
+		return "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 
 	parts := strings.SplitN(n, ".", 2)
 	if len(parts[0]) == 0 {
-		return "", fmt.Errorf("invalid amount")
+		return "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 	if len(parts) == 2 && (len(parts[1]) == 0 || len(parts[1]) > 2) {
-		return "", fmt.Errorf("invalid amount")
+		return "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 	if slices.Contains([]string{"", "."}, n) {
-		return "", fmt.Errorf("invalid amount")
+		return "", fieldErrorf("amount", CodeInvalid, "invalid amount")
 	}
 	return n, nil
 }
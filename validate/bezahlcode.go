@@ -0,0 +1,160 @@
+package validate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// BezahlCode URI authorities, reported as Clean.BezahlCodeKind.
+const (
+	BezahlCodeSinglePayment   = "singlepaymentsepa"
+	BezahlCodePeriodicPayment = "periodicsinglepaymentsepa"
+)
+
+// BezahlCode limits of the reason parameter: 140 characters of free text,
+// or a structured reference of up to 35 (the SEPA creditor reference).
+const (
+	BezahlCodeReasonMax    = 140
+	BezahlCodeReferenceMax = 35
+)
+
+var rePeriod = regexp.MustCompile(`^([1-9][0-9]?)([MW])$`)
+
+// cleanPeriod checks a standing-order period: a count followed by M
+// (months, up to 12) or W (weeks, up to 52), e.g. "1M" or "2W".
+func cleanPeriod(v string) (string, error) {
+	v = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(v), " ", ""))
+	if v == "" {
+		return "", nil
+	}
+	m := rePeriod.FindStringSubmatch(v)
+	if m == nil {
+		return "", fieldErrorf("period", CodeInvalid, "invalid period")
+	}
+	n, _ := strconv.Atoi(m[1])
+	if (m[2] == "M" && n > 12) || (m[2] == "W" && n > 52) {
+		return "", fieldErrorf("period", CodeInvalid, "invalid period")
+	}
+	return v, nil
+}
+
+func cleanBezahlCode(in Input) (*Clean, error) {
+	const scheme = SchemeBezahlCode
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"purpose", in.Purpose},
+		fieldValue{"information", in.Information},
+		fieldValue{"due_date", in.DueDate},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_street", in.CreditorStreet},
+		fieldValue{"creditor_building_number", in.CreditorBuildingNumber},
+		fieldValue{"creditor_postal_code", in.CreditorPostalCode},
+		fieldValue{"creditor_town", in.CreditorTown},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+	))
+	if cur := strings.ToUpper(strings.TrimSpace(in.Currency)); cur != "" && cur != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be EUR"))
+	}
+
+	// The URI is percent-encoded UTF-8, so only sepa_charset changes text.
+	sepaMode, err := parseSEPACharset(in.SEPACharset)
+	errs.add(err)
+	translits := []Transliteration{}
+	name, translits, err := cleanSEPAText(sepaMode, "name", oneLine.Replace(strings.TrimSpace(in.Name)), translits)
+	errs.add(err)
+	warnings := []Warning{}
+	name, warnings, err = truncateField("name", name, 70, warnings)
+	errs.add(err)
+	if name == "" && !errs.failed("name") {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case errs.add(checkSEPA(in, iban)):
+	}
+	bic := strings.ToUpper(strings.TrimSpace(in.BIC))
+	if !errs.failed("iban") {
+		bic, warnings = checkBIC(in, iban, bic, warnings)
+	}
+	if bic != "" && !reBIC.MatchString(bic) {
+		errs.add(fieldErrorf("bic", CodeInvalid, "invalid bic"))
+	}
+
+	amtCents, err := parseSchemeAmount(in, "EUR", 99999999999)
+	errs.add(err)
+
+	// BezahlCode has a single reason line; a reference goes there too.
+	// Typed references keep their canonical form, such as the "<" of dk_fik.
+	ref, refType, err := cleanReference(in, strings.TrimSpace(in.RemittanceReference))
+	if !errs.add(err) && refType == "" {
+		ref, translits, err = cleanSEPAText(sepaMode, "remittance_reference", ref, translits)
+		errs.add(err)
+	}
+	text, translits, err := cleanSEPAText(sepaMode, "remittance_text", oneLine.Replace(strings.TrimSpace(in.RemittanceText)), translits)
+	errs.add(err)
+	text, warnings, err = truncateField("remittance_text", text, BezahlCodeReasonMax, warnings)
+	errs.add(err)
+	ref, warnings, err = truncateField("remittance_reference", ref, BezahlCodeReferenceMax, warnings)
+	errs.add(err)
+	if ref != "" && text != "" {
+		errs.add(fieldErrorf("remittance_reference", CodeConflict, "remittance_reference and remittance_text are mutually exclusive"))
+	}
+
+	execDate := strings.TrimSpace(in.ExecutionDate)
+	if execDate != "" {
+		if _, err := time.Parse("2006-01-02", execDate); err != nil {
+			errs.add(fieldErrorf("execution_date", CodeInvalid, "invalid execution_date"))
+		}
+	}
+	period, err := cleanPeriod(in.Period)
+	errs.add(err)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	// The URI authority names the payment type.
+	kind := BezahlCodeSinglePayment
+	if period != "" {
+		kind = BezahlCodePeriodicPayment
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Charset:             charset.UTF8,
+		Name:                name,
+		IBAN:                iban,
+		BIC:                 bic,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            "EUR",
+		RemittanceReference: ref,
+		ReferenceType:       refType,
+		RemittanceText:      text,
+		ExecutionDate:       execDate,
+		Period:              period,
+		BezahlCodeKind:      kind,
+		Transliterations:    translits,
+		Warnings:            warnings,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanAndValidate_BezahlCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+			},
+		},
+		{
+			name: "no_bic",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+			},
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				RemittanceText: "Rechnung 17",
+				OpenAmount:     true,
+			},
+		},
+		{
+			name: "standing_order",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				ExecutionDate:  "2026-11-01",
+				Period:         "1m",
+			},
+		},
+		{
+			name: "rf_reference",
+			in: Input{
+				Scheme:              "bezahlcode",
+				Name:                "Max Müller",
+				IBAN:                "DE12500105170648489890",
+				BIC:                 "INGDDEFFXXX",
+				Amount:              "12.34",
+				RemittanceReference: "RF18 5390 0754 7034",
+			},
+		},
+		{
+			name: "execution_date_invalid",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				ExecutionDate:  "01.11.2026",
+			},
+			wantErr: "invalid execution_date",
+		},
+		{
+			name: "period_invalid_unit",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				Period:         "1Y",
+			},
+			wantErr: "invalid period",
+		},
+		{
+			name: "period_too_many_months",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				Period:         "13M",
+			},
+			wantErr: "invalid period",
+		},
+		{
+			name: "reference_and_text",
+			in: Input{
+				Scheme:              "bezahlcode",
+				Name:                "Max Müller",
+				IBAN:                "DE12500105170648489890",
+				BIC:                 "INGDDEFFXXX",
+				Amount:              "12.34",
+				RemittanceText:      "Rechnung 17",
+				RemittanceReference: "INV-17",
+			},
+			wantErr: "remittance_reference and remittance_text are mutually exclusive",
+		},
+		{
+			name: "rf_invalid",
+			in: Input{
+				Scheme:              "bezahlcode",
+				Name:                "Max Müller",
+				IBAN:                "DE12500105170648489890",
+				BIC:                 "INGDDEFFXXX",
+				Amount:              "12.34",
+				RemittanceReference: "RF19539007547034",
+			},
+			wantErr: "invalid creditor reference",
+		},
+		{
+			name: "currency_chf",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				Currency:       "CHF",
+			},
+			wantErr: "currency must be EUR",
+		},
+		{
+			name: "purpose_not_supported",
+			in: Input{
+				Scheme:         "bezahlcode",
+				Name:           "Max Müller",
+				IBAN:           "DE12500105170648489890",
+				BIC:            "INGDDEFFXXX",
+				Amount:         "12.34",
+				RemittanceText: "Rechnung 17",
+				Purpose:        "GDDS",
+			},
+			wantErr: "purpose is not supported by bezahlcode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_BezahlCodeKind(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:         "bezahlcode",
+		Name:           "Max Müller",
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "12.34",
+		RemittanceText: "Rechnung 17",
+	})
+	if err != nil || cleaned.BezahlCodeKind != BezahlCodeSinglePayment || cleaned.Version != "" {
+		t.Fatalf("unexpected clean: %+v, %v", cleaned, err)
+	}
+	cleaned, err = CleanAndValidate(Input{
+		Scheme:         "bezahlcode",
+		Name:           "Max Müller",
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "12.34",
+		RemittanceText: "Rechnung 17",
+		Period:         "2w",
+	})
+	if err != nil || cleaned.BezahlCodeKind != BezahlCodePeriodicPayment || cleaned.Period != "2W" {
+		t.Fatalf("unexpected periodic clean: %+v, %v", cleaned, err)
+	}
+
+	_, err = CleanAndValidate(Input{
+		Name:          "Example GmbH",
+		IBAN:          "DE12500105170648489890",
+		BIC:           "INGDDEFFXXX",
+		Amount:        "1",
+		ExecutionDate: "2026-11-01",
+	})
+	if err == nil || err.Error() != "execution_date is not supported by epc_sct" {
+		t.Fatalf("expected epc_sct to reject execution_date, got %v", err)
+	}
+}
+
+func TestCleanAndValidate_BezahlCodeReferenceLimit(t *testing.T) {
+	ref := strings.Repeat("7", BezahlCodeReferenceMax)
+	for _, period := range []string{"", "1M"} {
+		in := Input{
+			Scheme:              "bezahlcode",
+			Name:                "Max Müller",
+			IBAN:                "DE12500105170648489890",
+			BIC:                 "INGDDEFFXXX",
+			Amount:              "12.34",
+			RemittanceReference: ref,
+			Period:              period,
+		}
+		cleaned, err := CleanAndValidate(in)
+		if err != nil || cleaned.RemittanceReference != ref || len(cleaned.Warnings) != 0 {
+			t.Fatalf("period %q: %d characters must be kept: %+v, %v", period, len(ref), cleaned, err)
+		}
+
+		in.RemittanceReference = ref + "8"
+		cleaned, err = CleanAndValidate(in)
+		if err != nil || cleaned.RemittanceReference != ref || len(cleaned.Warnings) != 1 || cleaned.Warnings[0].KeptLength != BezahlCodeReferenceMax {
+			t.Fatalf("period %q: expected truncation to %d: %+v, %v", period, BezahlCodeReferenceMax, cleaned, err)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package validate
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// BankEntry is one bank of an offline BIC directory. CheckMethod is the
+// Bundesbank account check-digit method ("00".."E4") for German banks.
+type BankEntry struct {
+	Country     string
+	BankCode    string
+	BIC         string
+	Name        string
+	CheckMethod string
+}
+
+// BICDirectory maps the bank code inside an IBAN to its bank.
+type BICDirectory struct {
+	byCode map[string]BankEntry
+}
+
+// ibanBankCodes gives the position of the bank code inside the BBAN.
+var ibanBankCodes = map[string][2]int{
+	"AT": {0, 5}, "BE": {0, 3}, "BG": {0, 4}, "CH": {0, 5}, "CY": {0, 3},
+	"CZ": {0, 4}, "DE": {0, 8}, "DK": {0, 4}, "EE": {0, 2}, "ES": {0, 4},
+	"FI": {0, 3}, "FR": {0, 5}, "GB": {0, 4}, "GR": {0, 3}, "HR": {0, 7},
+	"HU": {0, 3}, "IE": {0, 4}, "IS": {0, 4}, "IT": {1, 5}, "LI": {0, 5},
+	"LT": {0, 5}, "LU": {0, 3}, "LV": {0, 4}, "MC": {0, 5}, "MT": {0, 4},
+	"NL": {0, 4}, "NO": {0, 4}, "PL": {0, 8}, "PT": {0, 4}, "RO": {0, 4},
+	"SE": {0, 3}, "SI": {0, 5}, "SK": {0, 4}, "SM": {1, 5},
+}
+
+// IBANBankCode returns the country and bank code of a normalized IBAN.
+func IBANBankCode(iban string) (string, string, bool) {
+	if len(iban) < 4 {
+		return "", "", false
+	}
+	pos, ok := ibanBankCodes[iban[:2]]
+	if !ok || len(iban) < 4+pos[0]+pos[1] {
+		return "", "", false
+	}
+	return iban[:2], iban[4+pos[0] : 4+pos[0]+pos[1]], true
+}
+
+// NewBICDirectory builds a directory from entries. Sources listed first
+// win; later entries for the same country and bank code only fill fields
+// the earlier ones left empty, such as the BIC of a branch record.
+func NewBICDirectory(entries []BankEntry) *BICDirectory {
+	d := &BICDirectory{byCode: make(map[string]BankEntry, len(entries))}
+	for _, e := range entries {
+		e.Country = strings.ToUpper(strings.TrimSpace(e.Country))
+		e.BankCode = strings.ToUpper(strings.TrimSpace(e.BankCode))
+		e.BIC = strings.ToUpper(strings.TrimSpace(e.BIC))
+		e.Name = strings.TrimSpace(e.Name)
+		e.CheckMethod = strings.ToUpper(strings.TrimSpace(e.CheckMethod))
+		if e.Country == "" || e.BankCode == "" {
+			continue
+		}
+		if e.BIC != "" && !reBIC.MatchString(e.BIC) {
+			continue
+		}
+		key := e.Country + e.BankCode
+		prev, dup := d.byCode[key]
+		if !dup {
+			d.byCode[key] = e
+			continue
+		}
+		if prev.BIC == "" {
+			prev.BIC = e.BIC
+		}
+		if prev.Name == "" {
+			prev.Name = e.Name
+		}
+		if prev.CheckMethod == "" {
+			prev.CheckMethod = e.CheckMethod
+		}
+		d.byCode[key] = prev
+	}
+	return d
+}
+
+// Len returns the number of banks in the directory.
+func (d *BICDirectory) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.byCode)
+}
+
+// Entries returns the banks sorted by country and bank code.
+func (d *BICDirectory) Entries() []BankEntry {
+	if d == nil {
+		return nil
+	}
+	out := make([]BankEntry, 0, len(d.byCode))
+	for _, e := range d.byCode {
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Country != out[j].Country {
+			return out[i].Country < out[j].Country
+		}
+		return out[i].BankCode < out[j].BankCode
+	})
+	return out
+}
+
+// Lookup returns the bank of a normalized IBAN.
+func (d *BICDirectory) Lookup(iban string) (BankEntry, bool) {
+	if d == nil {
+		return BankEntry{}, false
+	}
+	country, code, ok := IBANBankCode(iban)
+	if !ok {
+		return BankEntry{}, false
+	}
+	e, ok := d.byCode[country+code]
+	return e, ok
+}
+
+var bicDirectory atomic.Pointer[BICDirectory]
+
+// SetBICDirectory installs the directory used by CleanAndValidate; nil
+// turns directory checks off.
+func SetBICDirectory(d *BICDirectory) {
+	bicDirectory.Store(d)
+}
+
+// BICForIBAN derives the BIC of a normalized IBAN from the installed
+// directory.
+func BICForIBAN(iban string) (string, bool) {
+	e, ok := bicDirectory.Load().Lookup(iban)
+	if !ok || e.BIC == "" {
+		return "", false
+	}
+	return e.BIC, true
+}
+
+// checkBIC fills a missing BIC from the directory when the request asks
+// for it and reports BICs that do not belong to the IBAN's country or bank.
+func checkBIC(in Input, iban, bic string, warnings []Warning) (string, []Warning) {
+	entry, found := bicDirectory.Load().Lookup(iban)
+	if bic == "" {
+		if in.BICFromIBAN && found && entry.BIC != "" {
+			bic = entry.BIC
+			warnings = append(warnings, Warning{
+				Field:   "bic",
+				Code:    "bic_from_iban",
+				Message: fmt.Sprintf("bic %s derived from iban", bic),
+			})
+		}
+		return bic, warnings
+	}
+	if !reBIC.MatchString(bic) {
+		return bic, warnings
+	}
+	if country := ibanCountry(iban); bic[4:6] != country {
+		warnings = append(warnings, Warning{
+			Field:   "bic",
+			Code:    "bic_country_mismatch",
+			Message: fmt.Sprintf("bic country %s does not match iban country %s", bic[4:6], country),
+		})
+	}
+	if found && entry.BIC != "" && entry.BIC[:8] != bic[:8] {
+		warnings = append(warnings, Warning{
+			Field:   "bic",
+			Code:    "bic_bank_mismatch",
+			Message: fmt.Sprintf("bic does not match the iban's bank (directory: %s)", entry.BIC),
+		})
+	}
+	return bic, warnings
+}
+
+// Directory file: CSV with the header country,bank_code,bic,name,check_method.
+var bicDirectoryHeader = []string{"country", "bank_code", "bic", "name", "check_method"}
+
+// LoadBICDirectory reads a directory file written by WriteBICDirectory or
+// by hand (name and check_method may be omitted).
+func LoadBICDirectory(path string) (*BICDirectory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read bic directory: %w", err)
+	}
+	defer f.Close()
+	entries, err := ParseBankCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse bic directory: %w", err)
+	}
+	return NewBICDirectory(entries), nil
+}
+
+// WriteBICDirectory writes d in the directory file format.
+func WriteBICDirectory(w io.Writer, d *BICDirectory) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(bicDirectoryHeader); err != nil {
+		return err
+	}
+	for _, e := range d.Entries() {
+		if err := cw.Write([]string{e.Country, e.BankCode, e.BIC, e.Name, e.CheckMethod}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ParseBankCSV reads generic comma-separated rows of
+// country,bank_code,bic[,name[,check_method]]. A header row is skipped.
+func ParseBankCSV(r io.Reader) ([]BankEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var out []BankEntry
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(rec[0]), "country") {
+			continue
+		}
+		if len(rec) < 3 {
+			return nil, fmt.Errorf("line %d: want country,bank_code,bic", line)
+		}
+		e := BankEntry{Country: rec[0], BankCode: rec[1], BIC: rec[2]}
+		if len(rec) > 3 {
+			e.Name = rec[3]
+		}
+		if len(rec) > 4 {
+			e.CheckMethod = rec[4]
+		}
+		out = append(out, e)
+	}
+}
+
+// ParseBundesbankBLZ reads the Bundesbank bank code file (fixed-width,
+// ISO-8859-1, 168 characters per record). Records without a BIC are kept
+// for their check method; NewBICDirectory merges the records of a BLZ.
+func ParseBundesbankBLZ(r io.Reader) ([]BankEntry, error) {
+	var out []BankEntry
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		raw := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		rec, err := charset.Decode(raw, charset.ISO8859_1)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		runes := []rune(rec)
+		if len(runes) < 152 {
+			return nil, fmt.Errorf("line %d: record too short", line)
+		}
+		field := func(from, to int) string { return strings.TrimSpace(string(runes[from-1 : to])) }
+		out = append(out, BankEntry{
+			Country:     "DE",
+			BankCode:    field(1, 8),
+			BIC:         field(140, 150),
+			Name:        field(10, 67),
+			CheckMethod: field(151, 152),
+		})
+	}
+	return out, sc.Err()
+}
+
+// ParseBankTable reads a delimited bank list with a header row, such as
+// the OeNB list (";", "Bankleitzahl", "SWIFT-Code", "Bankenname") or the
+// Dutch BIC list (";", "Identifier", "BIC", "Naam betaaldienstverlener").
+// Lines before the header are skipped.
+func ParseBankTable(r io.Reader, country string, comma rune, codeCol, bicCol, nameCol string) ([]BankEntry, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	idx := map[string]int{}
+	var out []BankEntry
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(idx) == 0 {
+			for i, h := range rec {
+				idx[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+			}
+			_, hasCode := idx[codeCol]
+			_, hasBIC := idx[bicCol]
+			if !hasCode || !hasBIC {
+				idx = map[string]int{}
+			}
+			continue
+		}
+		get := func(col string) string {
+			if i, ok := idx[col]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		if get(codeCol) == "" || get(bicCol) == "" {
+			continue
+		}
+		out = append(out, BankEntry{Country: country, BankCode: get(codeCol), BIC: get(bicCol), Name: get(nameCol)})
+	}
+	if len(idx) == 0 {
+		return nil, fmt.Errorf("header with %q and %q not found", codeCol, bicCol)
+	}
+	return out, nil
+}
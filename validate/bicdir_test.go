@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// blzRecord builds one 168-character record of the Bundesbank bank code file.
+func blzRecord(blz, feature, name, bic, method string) string {
+	return fmt.Sprintf("%-8s%-1s%-58s%-5s%-35s%-27s%-5s%-11s%-2s%-6s%-1s%-1s%-8s",
+		blz, feature, name, "10591", "Berlin", name, "", bic, method, "000001", "U", "0", "00000000")
+}
+
+func testBICDirectory(t *testing.T) *BICDirectory {
+	t.Helper()
+	blz := strings.Join([]string{
+		blzRecord("50010517", "1", "ING-DiBa", "INGDDEFFXXX", "17"),
+		blzRecord("50010517", "2", "ING-DiBa", "", "17"),
+		blzRecord("70020270", "1", "UniCredit Bank - HypoVereinsbank M\xfcnchen", "HYVEDEMMXXX", "99"),
+		blzRecord("10010111", "1", "Testbank ohne BIC", "", "13"),
+	}, "\r\n")
+	de, err := ParseBundesbankBLZ(strings.NewReader(blz))
+	if err != nil {
+		t.Fatalf("ParseBundesbankBLZ: %v", err)
+	}
+	if len(de) != 4 || de[2].Name != "UniCredit Bank - HypoVereinsbank München" || de[0].CheckMethod != "17" || de[3].BIC != "" || de[3].CheckMethod != "13" {
+		t.Fatalf("unexpected Bundesbank entries: %+v", de)
+	}
+
+	at, err := ParseBankTable(strings.NewReader(
+		"Stand: 01.10.2026\nBankleitzahl;Bankenname;SWIFT-Code\n19043;Bank Austria;BKAUATWWXXX\n"),
+		"AT", ';', "Bankleitzahl", "SWIFT-Code", "Bankenname")
+	if err != nil {
+		t.Fatalf("ParseBankTable: %v", err)
+	}
+	other, err := ParseBankCSV(strings.NewReader("country,bank_code,bic\nBE,539,BBRUBEBB\n"))
+	if err != nil {
+		t.Fatalf("ParseBankCSV: %v", err)
+	}
+	return NewBICDirectory(append(append(de, at...), other...))
+}
+
+func TestBICDirectoryLookup(t *testing.T) {
+	d := testBICDirectory(t)
+	cases := []struct {
+		iban, bic string
+		ok        bool
+	}{
+		{"DE12500105170648489890", "INGDDEFFXXX", true},
+		{"AT611904300234573201", "BKAUATWWXXX", true},
+		{"BE68539007547034", "BBRUBEBB", true},
+		{"DE89370400440532013000", "", false},
+		{"GR1601101250000000012300695", "", false},
+	}
+	for _, tc := range cases {
+		e, ok := d.Lookup(tc.iban)
+		if ok != tc.ok || e.BIC != tc.bic {
+			t.Fatalf("%s: got %q %v, want %q %v", tc.iban, e.BIC, ok, tc.bic, tc.ok)
+		}
+	}
+}
+
+func TestBICDirectoryRoundTrip(t *testing.T) {
+	d := testBICDirectory(t)
+	var buf bytes.Buffer
+	if err := WriteBICDirectory(&buf, d); err != nil {
+		t.Fatalf("WriteBICDirectory: %v", err)
+	}
+	entries, err := ParseBankCSV(&buf)
+	if err != nil {
+		t.Fatalf("ParseBankCSV: %v", err)
+	}
+	got := NewBICDirectory(entries)
+	if got.Len() != d.Len() || fmt.Sprint(got.Entries()) != fmt.Sprint(d.Entries()) {
+		t.Fatalf("round trip mismatch:\n%v\n%v", got.Entries(), d.Entries())
+	}
+}
+
+func TestParseBankTableMissingHeader(t *testing.T) {
+	if _, err := ParseBankTable(strings.NewReader("a;b\n1;2\n"), "NL", ';', "Identifier", "BIC", ""); err == nil {
+		t.Fatalf("expected missing header error")
+	}
+}
+
+func TestCleanAndValidate_BICDirectory(t *testing.T) {
+	SetBICDirectory(testBICDirectory(t))
+	defer SetBICDirectory(nil)
+
+	warningCodes := func(c *Clean) string {
+		var codes []string
+		for _, w := range c.Warnings {
+			codes = append(codes, w.Code)
+		}
+		return strings.Join(codes, ",")
+	}
+	cases := []struct {
+		name     string
+		in       Input
+		bic      string
+		warnings string
+	}{
+		{"derived", Input{Version: "002", IBAN: "DE12500105170648489890", BICFromIBAN: true}, "INGDDEFFXXX", "bic_from_iban"},
+		{"not requested", Input{Version: "002", IBAN: "DE12500105170648489890"}, "", ""},
+		{"unknown bank", Input{Version: "002", IBAN: "DE89370400440532013000", BICFromIBAN: true}, "", ""},
+		{"matching", Input{IBAN: "DE12500105170648489890", BIC: "INGDDEFF"}, "INGDDEFF", ""},
+		{"bank mismatch", Input{IBAN: "DE12500105170648489890", BIC: "COBADEFFXXX"}, "COBADEFFXXX", "bic_bank_mismatch"},
+		{"country mismatch", Input{IBAN: "DE12500105170648489890", BIC: "BNPAFRPPXXX"}, "BNPAFRPPXXX", "bic_country_mismatch,bic_bank_mismatch"},
+		{"bezahlcode", Input{Scheme: SchemeBezahlCode, IBAN: "AT611904300234573201", BICFromIBAN: true}, "BKAUATWWXXX", "bic_from_iban"},
+	}
+	for _, tc := range cases {
+		tc.in.Name = "Example GmbH"
+		tc.in.Amount = "10.00"
+		c, err := CleanAndValidate(tc.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if c.BIC != tc.bic || warningCodes(c) != tc.warnings {
+			t.Fatalf("%s: got bic %q warnings %q, want %q %q", tc.name, c.BIC, warningCodes(c), tc.bic, tc.warnings)
+		}
+	}
+
+	// Version 001 still requires a BIC when none can be derived.
+	if _, err := CleanAndValidate(Input{Name: "Example", IBAN: "DE89370400440532013000", Amount: "1", BICFromIBAN: true}); err == nil || err.Error() != "bic is required" {
+		t.Fatalf("expected bic is required, got %v", err)
+	}
+}
+
+func TestNewBICDirectoryMergesBICLessEntries(t *testing.T) {
+	d := NewBICDirectory([]BankEntry{
+		{Country: "DE", BankCode: "50010517", CheckMethod: "17"},
+		{Country: "DE", BankCode: "50010517", BIC: "INGDDEFFXXX", Name: "ING-DiBa", CheckMethod: "00"},
+		{Country: "DE", BankCode: "10010111", CheckMethod: "13"},
+	})
+	want := []BankEntry{
+		{Country: "DE", BankCode: "10010111", CheckMethod: "13"},
+		{Country: "DE", BankCode: "50010517", BIC: "INGDDEFFXXX", Name: "ING-DiBa", CheckMethod: "17"},
+	}
+	if fmt.Sprint(d.Entries()) != fmt.Sprint(want) {
+		t.Fatalf("got %+v, want %+v", d.Entries(), want)
+	}
+}
@@ -0,0 +1,90 @@
+package validate
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// EPCMaxPayloadBytes is the EPC069-12 limit for the whole encoded payload.
+const EPCMaxPayloadBytes = 331
+
+// FieldBytes is the encoded size of one payload field.
+type FieldBytes struct {
+	Field    string `json:"field"`
+	Bytes    int    `json:"bytes"`
+	Chars    int    `json:"chars"`
+	MaxChars int    `json:"max_chars,omitempty"`
+}
+
+// ByteUsage breaks the encoded EPC payload size down per field. Overhead
+// covers the fixed header lines and the line separators.
+type ByteUsage struct {
+	Total     int          `json:"total"`
+	Limit     int          `json:"limit"`
+	Remaining int          `json:"remaining"`
+	Overhead  int          `json:"overhead"`
+	Fields    []FieldBytes `json:"fields"`
+}
+
+// PayloadTooLargeError is returned when the encoded payload exceeds
+// EPCMaxPayloadBytes. Usage holds the breakdown that caused it.
+type PayloadTooLargeError struct {
+	Usage ByteUsage
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("payload too large: %d bytes exceeds the %d byte limit", e.Usage.Total, e.Usage.Limit)
+}
+
+func epcByteUsage(c *Clean) ByteUsage {
+	amount := ""
+	if !c.OpenAmount {
+		amount = fmt.Sprintf("EUR%d.%02d", c.AmountCents/100, c.AmountCents%100)
+	}
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"bic", c.BIC, 11},
+		{"name", c.Name, 70},
+		{"iban", c.IBAN, 34},
+		{"amount", amount, 0},
+		{"purpose", c.Purpose, 4},
+		{"remittance_reference", c.RemittanceReference, 25},
+		{"remittance_text", c.RemittanceText, 140},
+		{"information", c.Information, 70},
+	}
+
+	// "BCD", version, charset and "SCT" plus 11 line separators.
+	overhead := len("BCD") + len(c.Version) + len(fmt.Sprintf("%d", c.Charset)) + len("SCT") + 11
+	u := ByteUsage{
+		Limit:    EPCMaxPayloadBytes,
+		Overhead: overhead,
+		Total:    overhead,
+		Fields:   make([]FieldBytes, 0, len(fields)),
+	}
+	for _, f := range fields {
+		fb := FieldBytes{
+			Field:    f.name,
+			Bytes:    encodedLen(f.value, c.Charset),
+			Chars:    utf8.RuneCountInString(f.value),
+			MaxChars: f.max,
+		}
+		u.Total += fb.Bytes
+		u.Fields = append(u.Fields, fb)
+	}
+	u.Remaining = u.Limit - u.Total
+	return u
+}
+
+// encodedLen returns the byte length of s in the EPC charset. All ISO-8859
+// parts are single-byte; s is expected to be transliterated already.
+func encodedLen(s string, cs int) int {
+	if cs == charset.UTF8 {
+		return len(s)
+	}
+	return utf8.RuneCountInString(s)
+}
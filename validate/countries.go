@@ -0,0 +1,70 @@
+package validate
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// eeaCountries lists the IBAN country codes of the European Economic Area
+// (EU member states plus Iceland, Liechtenstein and Norway).
+var eeaCountries = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CY": true, "CZ": true, "DE": true,
+	"DK": true, "EE": true, "ES": true, "FI": true, "FR": true, "GR": true,
+	"HR": true, "HU": true, "IE": true, "IS": true, "IT": true, "LI": true,
+	"LT": true, "LU": true, "LV": true, "MT": true, "NL": true, "NO": true,
+	"PL": true, "PT": true, "RO": true, "SE": true, "SI": true, "SK": true,
+}
+
+// sepaCountries lists the IBAN country codes of the SEPA schemes: the EEA
+// plus Andorra, Switzerland, the United Kingdom, Gibraltar, Monaco, San
+// Marino, Vatican City, Albania, Moldova, Montenegro and North Macedonia.
+// Territories such as the French overseas departments, Åland, Jersey,
+// Guernsey and the Isle of Man use the IBAN code of their parent country.
+var sepaCountries = map[string]bool{
+	"AD": true, "AL": true, "CH": true, "GB": true, "GI": true, "MC": true,
+	"MD": true, "ME": true, "MK": true, "SM": true, "VA": true,
+}
+
+var allowNonSEPA atomic.Bool
+
+// SetAllowNonSEPA turns off the SEPA reachability check for all requests.
+func SetAllowNonSEPA(enabled bool) {
+	allowNonSEPA.Store(enabled)
+}
+
+// NonSEPAError is returned when a SEPA-only scheme gets an IBAN from a
+// country outside SEPA.
+type NonSEPAError struct {
+	Country string
+}
+
+func (e *NonSEPAError) Error() string {
+	return fmt.Sprintf("iban country %s is not in SEPA", e.Country)
+}
+
+// checkSEPA rejects IBANs no SEPA payer can pay to, unless the request or
+// the global setting allows them.
+func checkSEPA(in Input, iban string) error {
+	if in.AllowNonSEPA || allowNonSEPA.Load() {
+		return nil
+	}
+	if country := ibanCountry(iban); !inSEPA(country) {
+		return &NonSEPAError{Country: country}
+	}
+	return nil
+}
+
+func inSEPA(country string) bool {
+	return eeaCountries[country] || sepaCountries[country]
+}
+
+func ibanCountry(iban string) string {
+	if len(iban) < 2 {
+		return ""
+	}
+	return iban[:2]
+}
+
+func inEEA(country string) bool {
+	return eeaCountries[country]
+}
@@ -0,0 +1,40 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanAndValidate_SEPAReachability(t *testing.T) {
+	in := Input{Name: "Example Co", IBAN: "SA0380000000608010167519", BIC: "RIBLSARI", Amount: "10"}
+	_, err := CleanAndValidate(in)
+	var nonSEPA *NonSEPAError
+	if !errors.As(err, &nonSEPA) || nonSEPA.Country != "SA" || err.Error() != "iban country SA is not in SEPA" {
+		t.Fatalf("expected non-SEPA error, got %v", err)
+	}
+
+	in.AllowNonSEPA = true
+	if _, err := CleanAndValidate(in); err != nil {
+		t.Fatalf("per-request override: %v", err)
+	}
+
+	in.AllowNonSEPA = false
+	SetAllowNonSEPA(true)
+	_, err = CleanAndValidate(in)
+	SetAllowNonSEPA(false)
+	if err != nil {
+		t.Fatalf("global override: %v", err)
+	}
+
+	in.Scheme = SchemeBezahlCode
+	if _, err := CleanAndValidate(in); !errors.As(err, &nonSEPA) {
+		t.Fatalf("expected bezahlcode to reject non-SEPA IBAN, got %v", err)
+	}
+
+	// Non-EEA SEPA members pass.
+	for _, iban := range []string{"CH9300762011623852957", "GB82WEST12345698765432"} {
+		if _, err := CleanAndValidate(Input{Name: "Example", IBAN: iban, BIC: "UBSWCHZH80A", Amount: "10"}); err != nil {
+			t.Fatalf("%s: %v", iban, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var (
+	reSymbol = regexp.MustCompile(`^[0-9]{1,10}$`)
+	reSPDRef = regexp.MustCompile(`^[0-9]{1,16}$`)
+)
+
+// cleanDueDate normalizes an ISO 8601 date (YYYY-MM-DD).
+func cleanDueDate(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", v); err != nil {
+		return "", fieldErrorf("due_date", CodeInvalid, "invalid due_date")
+	}
+	return v, nil
+}
+
+// cleanSymbol checks a Czech/Slovak payment symbol: up to maxDigits digits.
+func cleanSymbol(field, v string, maxDigits int) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", nil
+	}
+	if !reSymbol.MatchString(v) || len(v) > maxDigits {
+		return "", fieldErrorf(field, CodeInvalid, "invalid %s", field)
+	}
+	return v, nil
+}
+
+func cleanCZSPD(in Input) (*Clean, error) {
+	const scheme = SchemeCZSPD
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"purpose", in.Purpose},
+		fieldValue{"rf_from_invoice", in.RFFromInvoice},
+		fieldValue{"information", in.Information},
+		fieldValue{"creditor_street", in.CreditorStreet},
+		fieldValue{"creditor_building_number", in.CreditorBuildingNumber},
+		fieldValue{"creditor_postal_code", in.CreditorPostalCode},
+		fieldValue{"creditor_town", in.CreditorTown},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = "CZK"
+	}
+	if currency != "CZK" && currency != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be CZK or EUR"))
+	}
+
+	name, warnings, err := truncateField("name", strings.TrimSpace(in.Name), 35, []Warning{})
+	errs.add(err)
+	if name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	if iban == "" {
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	} else {
+		errs.add(CheckIBAN(iban))
+	}
+	bic := strings.ToUpper(strings.TrimSpace(in.BIC))
+	if !errs.failed("iban") {
+		bic, warnings = checkBIC(in, iban, bic, warnings)
+	}
+	if bic != "" && !reBIC.MatchString(bic) {
+		errs.add(fieldErrorf("bic", CodeInvalid, "invalid bic"))
+	}
+
+	// AM holds at most 10 characters, i.e. 9999999.99.
+	var amtCents int64
+	if !errs.failed("currency") {
+		amtCents, err = parseSchemeAmount(in, currency, 999999999)
+		errs.add(err)
+	}
+
+	// RF in SPD is the payee's numeric payment identifier, up to 16 digits.
+	ref := strings.ReplaceAll(strings.TrimSpace(in.RemittanceReference), " ", "")
+	if ref != "" && !reSPDRef.MatchString(ref) {
+		errs.add(fieldErrorf("remittance_reference", CodeInvalid, "remittance_reference must be up to 16 digits for cz_spd"))
+	}
+
+	dueDate, err := cleanDueDate(in.DueDate)
+	errs.add(err)
+	vs, err := cleanSymbol("variable_symbol", in.VariableSymbol, 10)
+	errs.add(err)
+	ks, err := cleanSymbol("constant_symbol", in.ConstantSymbol, 10)
+	errs.add(err)
+	ss, err := cleanSymbol("specific_symbol", in.SpecificSymbol, 10)
+	errs.add(err)
+	message, warnings, err := truncateField("remittance_text", strings.TrimSpace(in.RemittanceText), 60, warnings)
+	errs.add(err)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "1.0",
+		Charset:             charset.UTF8,
+		Name:                name,
+		IBAN:                iban,
+		BIC:                 bic,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            currency,
+		RemittanceReference: ref,
+		RemittanceText:      message,
+		DueDate:             dueDate,
+		VariableSymbol:      vs,
+		ConstantSymbol:      ks,
+		SpecificSymbol:      ss,
+		Transliterations:    []Transliteration{},
+		Warnings:            warnings,
+	}, nil
+}
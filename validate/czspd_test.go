@@ -0,0 +1,214 @@
+package validate
+
+import "testing"
+
+func TestCleanAndValidate_CZSPD(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+			},
+		},
+		{
+			name: "eur_with_bic",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				Currency:       "eur",
+				BIC:            "GIBACZPX",
+			},
+		},
+		{
+			name: "all_symbols",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				ConstantSymbol: "0308",
+				SpecificSymbol: "42",
+				RemittanceText: "Faktura 2026/17",
+			},
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				OpenAmount:     true,
+			},
+		},
+		{
+			name: "numeric_reference",
+			in: Input{
+				Scheme:              "cz_spd",
+				Name:                "Example s.r.o.",
+				IBAN:                "CZ65 0800 0000 1920 0014 5399",
+				Amount:              "450,00",
+				VariableSymbol:      "1234567890",
+				DueDate:             "2026-11-30",
+				RemittanceReference: "1234567890123456",
+			},
+		},
+		{
+			name: "reference_not_numeric",
+			in: Input{
+				Scheme:              "cz_spd",
+				Name:                "Example s.r.o.",
+				IBAN:                "CZ65 0800 0000 1920 0014 5399",
+				Amount:              "450,00",
+				VariableSymbol:      "1234567890",
+				DueDate:             "2026-11-30",
+				RemittanceReference: "INV-1",
+			},
+			wantErr: "remittance_reference must be up to 16 digits for cz_spd",
+		},
+		{
+			name: "currency_unsupported",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				Currency:       "CHF",
+			},
+			wantErr: "currency must be CZK or EUR",
+		},
+		{
+			name: "amount_too_large",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "10000000",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+			},
+			wantErr: "amount too large",
+		},
+		{
+			name: "variable_symbol_too_long",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "12345678901",
+				DueDate:        "2026-11-30",
+			},
+			wantErr: "invalid variable_symbol",
+		},
+		{
+			name: "constant_symbol_letters",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				ConstantSymbol: "03A8",
+			},
+			wantErr: "invalid constant_symbol",
+		},
+		{
+			name: "due_date_invalid",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "30.11.2026",
+			},
+			wantErr: "invalid due_date",
+		},
+		{
+			name: "bic_invalid",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				BIC:            "GIBA",
+			},
+			wantErr: "invalid bic",
+		},
+		{
+			name: "information_not_supported",
+			in: Input{
+				Scheme:         "cz_spd",
+				Name:           "Example s.r.o.",
+				IBAN:           "CZ65 0800 0000 1920 0014 5399",
+				Amount:         "450,00",
+				VariableSymbol: "1234567890",
+				DueDate:        "2026-11-30",
+				Information:    "x",
+			},
+			wantErr: "information is not supported by cz_spd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_CZSPDDefaults(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:         "cz_spd",
+		Name:           "Example s.r.o.",
+		IBAN:           "CZ65 0800 0000 1920 0014 5399",
+		Amount:         "450,00",
+		VariableSymbol: "1234567890",
+		DueDate:        "2026-11-30",
+		AmountFormat:   "eur_comma",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.Currency != "CZK" || cleaned.AmountCents != 45000 || cleaned.IBAN != "CZ6508000000192000145399" {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+
+	_, err = CleanAndValidate(Input{
+		Name:           "Example GmbH",
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "1",
+		VariableSymbol: "123",
+	})
+	if err == nil || err.Error() != "variable_symbol is not supported by epc_sct" {
+		t.Fatalf("expected epc_sct to reject variable_symbol, got %v", err)
+	}
+}
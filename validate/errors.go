@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError is one failed check on one input field. Code is stable and
+// meant for programs; Message is the text CleanAndValidate has always
+// returned for the check.
+type FieldError struct {
+	Code    string `json:"code"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+
+	err error
+}
+
+func (e *FieldError) Error() string { return e.Message }
+
+// Unwrap returns the typed cause (NonSEPAError, PayloadTooLargeError), if any.
+func (e *FieldError) Unwrap() error { return e.err }
+
+// Error codes of FieldError.
+const (
+	CodeRequired           = "required"
+	CodeInvalid            = "invalid"
+	CodeInvalidLength      = "invalid_length"
+	CodeInvalidFormat      = "invalid_format"
+	CodeInvalidCheckDigit  = "invalid_check_digit"
+	CodeInvalidCharacter   = "invalid_character"
+	CodeUnsupported        = "unsupported"
+	CodeConflict           = "conflict"
+	CodeTooLarge           = "too_large"
+	CodeTooLong            = "too_long"
+	CodeUnknownPurposeCode = "unknown_purpose_code"
+	CodeNonSEPAIBAN        = "non_sepa_iban"
+	CodePayloadTooLarge    = "payload_too_large"
+	CodeOCRAmbiguous       = "ocr_ambiguous"
+)
+
+func fieldErrorf(field, code, format string, args ...any) *FieldError {
+	return &FieldError{Code: code, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidationError lists every failed check of one input in check order.
+// Error returns the first message, so callers that only show one error
+// see the same text as before.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (e *ValidationError) Error() string { return e.Errors[0].Message }
+
+func (e *ValidationError) Unwrap() []error {
+	out := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		out[i] = fe
+	}
+	return out
+}
+
+// FieldErrors returns every failed check in err, or nil when err does not
+// come from CleanAndValidate.
+func FieldErrors(err error) []*FieldError {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.Errors
+	}
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return []*FieldError{fe}
+	}
+	return nil
+}
+
+// asFieldError turns any validation failure into a FieldError.
+func asFieldError(err error) *FieldError {
+	var fe *FieldError
+	if errors.As(err, &fe) {
+		return fe
+	}
+	var nonSEPA *NonSEPAError
+	if errors.As(err, &nonSEPA) {
+		return &FieldError{Code: CodeNonSEPAIBAN, Field: "iban", Message: err.Error(), err: err}
+	}
+	var tooLarge *PayloadTooLargeError
+	if errors.As(err, &tooLarge) {
+		return &FieldError{Code: CodePayloadTooLarge, Message: err.Error(), err: err}
+	}
+	return &FieldError{Code: CodeInvalid, Message: err.Error(), err: err}
+}
+
+// errorList collects the failed checks of one input. Checks keep running
+// after a failure unless they depend on the failed field.
+type errorList struct {
+	errs []*FieldError
+}
+
+// add records err, if any, and reports whether there was one.
+func (l *errorList) add(err error) bool {
+	if err == nil {
+		return false
+	}
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		l.errs = append(l.errs, ve.Errors...)
+		return true
+	}
+	l.errs = append(l.errs, asFieldError(err))
+	return true
+}
+
+// failed reports whether a check on field has failed.
+func (l *errorList) failed(field string) bool {
+	for _, fe := range l.errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// err returns the collected failures as a *ValidationError, or nil.
+func (l *errorList) err() error {
+	if len(l.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: l.errs}
+}
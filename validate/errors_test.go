@@ -0,0 +1,73 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCleanAndValidate_AllFieldErrors(t *testing.T) {
+	_, err := CleanAndValidate(Input{
+		IBAN:           "DE12500105170648489891",
+		Amount:         "-1",
+		Purpose:        "ZZZZ",
+		ExecutionDate:  "2026-01-01",
+		SEPACharset:    "latin1",
+		VariableSymbol: "12",
+	})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected *ValidationError, got %T %v", err, err)
+	}
+	want := []struct{ field, code string }{
+		{"execution_date", CodeUnsupported},
+		{"variable_symbol", CodeUnsupported},
+		{"sepa_charset", CodeUnsupported},
+		{"name", CodeRequired},
+		{"iban", CodeInvalid},
+		{"bic", CodeRequired},
+		{"amount", CodeInvalid},
+		{"purpose", CodeUnknownPurposeCode},
+	}
+	got := map[string]string{}
+	for _, fe := range ve.Errors {
+		got[fe.Field] = fe.Code
+	}
+	for _, w := range want {
+		if got[w.field] != w.code {
+			t.Errorf("field %s: got code %q, want %q (all: %+v)", w.field, got[w.field], w.code, got)
+		}
+	}
+	if err.Error() != ve.Errors[0].Message {
+		t.Fatalf("Error() = %q, want first message %q", err.Error(), ve.Errors[0].Message)
+	}
+}
+
+func TestCleanAndValidate_FieldErrorsSkipDependentChecks(t *testing.T) {
+	// A Swiss bill with a bad IBAN cannot tell whether a QR reference is
+	// required, so only the IBAN is reported.
+	_, err := CleanAndValidate(Input{
+		Scheme:             SchemeSwissQR,
+		Name:               "Example AG",
+		IBAN:               "CH00",
+		Amount:             "10",
+		CreditorPostalCode: "8000",
+		CreditorTown:       "Zurich",
+		CreditorCountry:    "CH",
+	})
+	fieldErrs := FieldErrors(err)
+	if len(fieldErrs) != 1 || fieldErrs[0].Field != "iban" {
+		t.Fatalf("expected one iban error, got %+v", fieldErrs)
+	}
+}
+
+func TestFieldErrors_NonSEPA(t *testing.T) {
+	_, err := CleanAndValidate(Input{Name: "Example", IBAN: "SA0380000000608010167519", BIC: "RJHISARI", Amount: "1"})
+	var nonSEPA *NonSEPAError
+	if !errors.As(err, &nonSEPA) {
+		t.Fatalf("expected *NonSEPAError in chain, got %v", err)
+	}
+	fieldErrs := FieldErrors(err)
+	if len(fieldErrs) != 1 || fieldErrs[0].Field != "iban" || fieldErrs[0].Code != CodeNonSEPAIBAN {
+		t.Fatalf("unexpected field errors: %+v", fieldErrs)
+	}
+}
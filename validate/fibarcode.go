@@ -0,0 +1,136 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var (
+	reFIRef    = regexp.MustCompile(`^[0-9]{4,20}$`)
+	reFIRFBody = regexp.MustCompile(`^[0-9]{1,21}$`)
+)
+
+// ValidFIReference reports whether ref is a Finnish national reference
+// (viitenumero): 4 to 20 digits, the last a check digit over the others
+// with weights 7, 3, 1 from the right.
+func ValidFIReference(ref string) bool {
+	if !reFIRef.MatchString(ref) {
+		return false
+	}
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	body := ref[:len(ref)-1]
+	for i := 0; i < len(body); i++ {
+		sum += int(body[len(body)-1-i]-'0') * weights[i%3]
+	}
+	return (10-sum%10)%10 == int(ref[len(ref)-1]-'0')
+}
+
+func cleanFIBarcode(in Input) (*Clean, error) {
+	const scheme = SchemeFIBarcode
+
+	// The barcode carries only account, amount, reference and due date.
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"name", in.Name},
+		fieldValue{"bic", in.BIC},
+		fieldValue{"purpose", in.Purpose},
+		fieldValue{"remittance_text", in.RemittanceText},
+		fieldValue{"information", in.Information},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_street", in.CreditorStreet},
+		fieldValue{"creditor_building_number", in.CreditorBuildingNumber},
+		fieldValue{"creditor_postal_code", in.CreditorPostalCode},
+		fieldValue{"creditor_town", in.CreditorTown},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+	if cur := strings.ToUpper(strings.TrimSpace(in.Currency)); cur != "" && cur != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be EUR"))
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case ibanCountry(iban) != "FI":
+		errs.add(fieldErrorf("iban", CodeUnsupported, "iban must be a FI IBAN"))
+	}
+
+	// Six digits of euros and two of cents; open_amount encodes zeros.
+	amtCents, err := parseSchemeAmount(in, "EUR", 99999999)
+	errs.add(err)
+
+	// Version 4 carries a national reference, version 5 a numeric RF one.
+	ref := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.RemittanceReference), " ", ""))
+	refOK := true
+	if rfFrom := strings.TrimSpace(in.RFFromInvoice); rfFrom != "" {
+		if ref != "" {
+			refOK = !errs.add(fieldErrorf("rf_from_invoice", CodeConflict, "remittance_reference and rf_from_invoice are mutually exclusive"))
+		} else {
+			ref, err = BuildRF(rfFrom)
+			refOK = !errs.add(err)
+		}
+	}
+	var version, refType string
+	switch {
+	case !refOK:
+	case ref == "":
+		errs.add(fieldErrorf("remittance_reference", CodeRequired, "remittance_reference is required"))
+	case strings.HasPrefix(ref, "RF"):
+		if !ValidRF(ref) {
+			errs.add(fieldErrorf("remittance_reference", CodeInvalidCheckDigit, "invalid creditor reference"))
+		} else if !reFIRFBody.MatchString(ref[4:]) {
+			errs.add(fieldErrorf("remittance_reference", CodeInvalid, "invalid fi reference"))
+		}
+		version, refType = "5", "RF"
+	default:
+		ref = strings.TrimLeft(ref, "0")
+		if !ValidFIReference(ref) {
+			errs.add(fieldErrorf("remittance_reference", CodeInvalid, "invalid fi reference"))
+		}
+		version, refType = "4", "FI"
+	}
+
+	// The barcode stores the due date as YYMMDD.
+	dueDate, err := cleanDueDate(in.DueDate)
+	if !errs.add(err) && dueDate != "" && !strings.HasPrefix(dueDate, "20") {
+		errs.add(fieldErrorf("due_date", CodeInvalid, "invalid due_date"))
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             version,
+		Charset:             charset.UTF8,
+		IBAN:                iban,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            "EUR",
+		RemittanceReference: ref,
+		ReferenceType:       refType,
+		DueDate:             dueDate,
+		Transliterations:    []Transliteration{},
+		Warnings:            []Warning{},
+	}, nil
+}
@@ -0,0 +1,223 @@
+package validate
+
+import "testing"
+
+func TestValidFIReference(t *testing.T) {
+	for _, ref := range []string{"12345672", "868516259619897", "1232"} {
+		if !ValidFIReference(ref) {
+			t.Fatalf("expected %q to be valid", ref)
+		}
+	}
+	for _, ref := range []string{"", "123", "12345671", "1234567A", "123456789012345678901"} {
+		if ValidFIReference(ref) {
+			t.Fatalf("expected %q to be invalid", ref)
+		}
+	}
+}
+
+func TestCleanAndValidate_FIBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2010-06-12",
+			},
+		},
+		{
+			name: "rf_reference",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "RF09 8685 1625 9619 897",
+				DueDate:             "2010-06-12",
+			},
+		},
+		{
+			name: "rf_from_invoice",
+			in: Input{
+				Scheme:        "fi_barcode",
+				IBAN:          "FI79 4405 2020 0360 82",
+				Amount:        "4883.15",
+				DueDate:       "2010-06-12",
+				RFFromInvoice: "868516259619897",
+			},
+		},
+		{
+			name: "open_amount_no_due_date",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				RemittanceReference: "86851 62596 19897",
+				OpenAmount:          true,
+			},
+		},
+		{
+			name: "leading_zeros",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "0012345672",
+				DueDate:             "2010-06-12",
+			},
+		},
+		{
+			name: "reference_required",
+			in: Input{
+				Scheme:  "fi_barcode",
+				IBAN:    "FI79 4405 2020 0360 82",
+				Amount:  "4883.15",
+				DueDate: "2010-06-12",
+			},
+			wantErr: "remittance_reference is required",
+		},
+		{
+			name: "reference_check_digit",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "12345671",
+				DueDate:             "2010-06-12",
+			},
+			wantErr: "invalid fi reference",
+		},
+		{
+			name: "rf_alphanumeric",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "RF45ABC",
+				DueDate:             "2010-06-12",
+			},
+			wantErr: "invalid fi reference",
+		},
+		{
+			name: "rf_check_digits",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "RF10868516259619897",
+				DueDate:             "2010-06-12",
+			},
+			wantErr: "invalid creditor reference",
+		},
+		{
+			name: "iban_not_fi",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "DE12500105170648489890",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2010-06-12",
+			},
+			wantErr: "iban must be a FI IBAN",
+		},
+		{
+			name: "amount_too_large",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "1000000.00",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2010-06-12",
+			},
+			wantErr: "amount too large",
+		},
+		{
+			name: "due_date_invalid",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "12.06.2010",
+			},
+			wantErr: "invalid due_date",
+		},
+		{
+			name: "due_date_out_of_range",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2110-06-12",
+			},
+			wantErr: "invalid due_date",
+		},
+		{
+			name: "currency_sek",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2010-06-12",
+				Currency:            "SEK",
+			},
+			wantErr: "currency must be EUR",
+		},
+		{
+			name: "name_not_supported",
+			in: Input{
+				Scheme:              "fi_barcode",
+				IBAN:                "FI79 4405 2020 0360 82",
+				Amount:              "4883.15",
+				RemittanceReference: "86851 62596 19897",
+				DueDate:             "2010-06-12",
+				Name:                "Yritys Oy",
+			},
+			wantErr: "name is not supported by fi_barcode",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_FIBarcodeVersion(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:              "fi_barcode",
+		IBAN:                "FI79 4405 2020 0360 82",
+		Amount:              "4883.15",
+		RemittanceReference: "86851 62596 19897",
+		DueDate:             "2010-06-12",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.Version != "4" || cleaned.ReferenceType != "FI" || cleaned.RemittanceReference != "868516259619897" || cleaned.AmountCents != 488315 {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+
+	cleaned, err = CleanAndValidate(Input{
+		Scheme:              "fi_barcode",
+		IBAN:                "FI79 4405 2020 0360 82",
+		Amount:              "4883.15",
+		RemittanceReference: "RF09868516259619897",
+		DueDate:             "2010-06-12",
+	})
+	if err != nil || cleaned.Version != "5" || cleaned.ReferenceType != "RF" {
+		t.Fatalf("unexpected RF clean: %+v, %v", cleaned, err)
+	}
+}
@@ -2,6 +2,7 @@ package validate
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -194,6 +195,8 @@ func FuzzValidIBAN(f *testing.F) {
 		"DE12500105170648489890",
 		"GB82WEST12345698765432",
 		"FR1420041010050500013M02606",
+		"DE605001051706484898901",
+		"FR43A0041010050500013M02606",
 		"INVALID",
 		"",
 	}
@@ -201,7 +204,23 @@ func FuzzValidIBAN(f *testing.F) {
 		f.Add(s)
 	}
 	f.Fuzz(func(t *testing.T, s string) {
-		_ = ValidIBAN(s)
+		err := CheckIBAN(s)
+		if err != nil {
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != "iban" {
+				t.Fatalf("rejected %q without an iban FieldError: %#v", s, err)
+			}
+			return
+		}
+		if len(s) != ibanRegistry[s[:2]].length {
+			t.Fatalf("accepted %q with registry length %d", s, ibanRegistry[s[:2]].length)
+		}
+		if !ibanBBANPatterns[s[:2]].MatchString(s[4:]) {
+			t.Fatalf("accepted %q with a BBAN outside the registry structure", s)
+		}
+		if mod, ok := mod97(s[4:] + s[:4]); !ok || mod != 1 {
+			t.Fatalf("accepted %q with mod 97 remainder %d", s, mod)
+		}
 	})
 }
 
@@ -0,0 +1,160 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var (
+	reHRModel = regexp.MustCompile(`^HR[0-9]{2}$`)
+	reHRPart  = regexp.MustCompile(`^[0-9]{1,12}$`)
+)
+
+// ValidHRReference reports whether ref is a Croatian model and reference
+// number such as HR01 + "1234-5678-9": up to three numeric parts of at most
+// 12 digits, 22 characters in total. Model HR99 carries no number. Check
+// digits of the individual models are not verified.
+func ValidHRReference(ref string) bool {
+	if len(ref) < 4 || !reHRModel.MatchString(ref[:4]) {
+		return false
+	}
+	number := ref[4:]
+	if ref[:4] == "HR99" {
+		return number == ""
+	}
+	if number == "" || len(number) > 22 {
+		return false
+	}
+	parts := strings.Split(number, "-")
+	if len(parts) > 3 {
+		return false
+	}
+	for _, p := range parts {
+		if !reHRPart.MatchString(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func cleanHRHUB3(in Input) (*Clean, error) {
+	const scheme = SchemeHRHUB3
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"bic", in.BIC},
+		fieldValue{"rf_from_invoice", in.RFFromInvoice},
+		fieldValue{"information", in.Information},
+		fieldValue{"due_date", in.DueDate},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+	if in.OpenAmount {
+		errs.add(fieldErrorf("open_amount", CodeUnsupported, "open_amount is not supported by %s", scheme))
+	}
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = "EUR"
+	}
+	if currency != "EUR" && currency != "HRK" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be EUR or HRK"))
+	}
+
+	translits := []Transliteration{}
+	warnings := []Warning{}
+	text := func(field, v string, max int) string {
+		v, translits = transliterateAllowed(field, strings.TrimSpace(v), latin2Allowed, translits)
+		var err error
+		v, warnings, err = truncateField(field, v, max, warnings)
+		errs.add(err)
+		return v
+	}
+
+	// HUB3 addresses are two free lines: street and number, postal code and town.
+	creditor := Address{
+		Name:   text("name", in.Name, 25),
+		Street: text("creditor_street", joinLine(in.CreditorStreet, in.CreditorBuildingNumber), 25),
+		Town:   text("creditor_town", joinLine(in.CreditorPostalCode, in.CreditorTown), 27),
+	}
+	if creditor.Name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	debtor := Address{
+		Name:   text("debtor_name", in.DebtorName, 30),
+		Street: text("debtor_street", joinLine(in.DebtorStreet, in.DebtorBuildingNumber), 27),
+		Town:   text("debtor_town", joinLine(in.DebtorPostalCode, in.DebtorTown), 27),
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case ibanCountry(iban) != "HR":
+		errs.add(fieldErrorf("iban", CodeUnsupported, "iban must be a HR IBAN"))
+	}
+
+	// 15 digits in cents. An open amount is already reported above.
+	var amtCents int64
+	if !in.OpenAmount && !errs.failed("currency") {
+		var err error
+		amtCents, err = parseSchemeAmount(in, currency, 999999999999999)
+		errs.add(err)
+	}
+
+	// Model and reference number; no reference means model HR99.
+	ref := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.RemittanceReference), " ", ""))
+	if ref == "" {
+		ref = "HR99"
+	}
+	if !ValidHRReference(ref) {
+		errs.add(fieldErrorf("remittance_reference", CodeInvalid, "invalid hr reference"))
+	}
+
+	description := text("remittance_text", in.RemittanceText, 35)
+	if description == "" {
+		errs.add(fieldErrorf("remittance_text", CodeRequired, "remittance_text is required"))
+	}
+	purpose := strings.TrimSpace(in.Purpose)
+	if purpose != "" {
+		var err error
+		purpose, warnings, err = checkPurpose(purpose, warnings)
+		errs.add(err)
+		purpose, warnings, err = truncateField("purpose", purpose, 4, warnings)
+		errs.add(err)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "HRVHUB30",
+		Charset:             charset.ISO8859_2,
+		Name:                creditor.Name,
+		IBAN:                iban,
+		AmountCents:         amtCents,
+		Currency:            currency,
+		Purpose:             purpose,
+		RemittanceReference: ref,
+		ReferenceType:       ref[:4],
+		RemittanceText:      description,
+		Creditor:            creditor,
+		Debtor:              debtor,
+		Transliterations:    translits,
+		Warnings:            warnings,
+	}, nil
+}
@@ -0,0 +1,235 @@
+package validate
+
+import "testing"
+
+func TestValidHRReference(t *testing.T) {
+	valid := []string{"HR99", "HR001234", "HR011234-5678-9", "HR67123456789012"}
+	for _, ref := range valid {
+		if !ValidHRReference(ref) {
+			t.Fatalf("expected %q to be valid", ref)
+		}
+	}
+	invalid := []string{"", "HR", "HR01", "HR991", "HR1A123", "HR011-2-3-4", "HR01123-", "HR011234567890123", "SI121234"}
+	for _, ref := range invalid {
+		if ValidHRReference(ref) {
+			t.Fatalf("expected %q to be invalid", ref)
+		}
+	}
+}
+
+func TestCleanAndValidate_HRHUB3(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+		},
+		{
+			name: "hrk",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "950.00",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+				Currency:               "hrk",
+			},
+		},
+		{
+			name: "no_reference",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+		},
+		{
+			name: "purpose",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+				Purpose:                "othr",
+			},
+		},
+		{
+			name: "currency_chf",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+				Currency:               "CHF",
+			},
+			wantErr: "currency must be EUR or HRK",
+		},
+		{
+			name: "iban_not_hr",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "DE12500105170648489890",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+			wantErr: "iban must be a HR IBAN",
+		},
+		{
+			name: "reference_invalid",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01ABC",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+			wantErr: "invalid hr reference",
+		},
+		{
+			name: "rf_reference",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "RF18539007547034",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+			wantErr: "invalid hr reference",
+		},
+		{
+			name: "remittance_text_required",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+			},
+			wantErr: "remittance_text is required",
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+				OpenAmount:             true,
+			},
+			wantErr: "open_amount is not supported by hr_hub3",
+		},
+		{
+			name: "bic_not_supported",
+			in: Input{
+				Scheme:                 "hr_hub3",
+				Name:                   "Primatelj d.o.o.",
+				IBAN:                   "HR12 1001 0051 8630 0016 0",
+				Amount:                 "EUR 125.50",
+				RemittanceReference:    "HR01 1234-5678",
+				RemittanceText:         "Račun 17",
+				CreditorStreet:         "Ilica",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "10000",
+				CreditorTown:           "Zagreb",
+				BIC:                    "ZABAHR2X",
+			},
+			wantErr: "bic is not supported by hr_hub3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_HRHUB3Defaults(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:                 "hr_hub3",
+		Name:                   "Primatelj d.o.o.",
+		IBAN:                   "HR12 1001 0051 8630 0016 0",
+		Amount:                 "EUR 125.50",
+		RemittanceText:         "Račun 17",
+		CreditorStreet:         "Ilica",
+		CreditorBuildingNumber: "1",
+		CreditorPostalCode:     "10000",
+		CreditorTown:           "Zagreb",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.Currency != "EUR" || cleaned.AmountCents != 12550 || cleaned.RemittanceReference != "HR99" || cleaned.ReferenceType != "HR99" {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+	if cleaned.Creditor.Street != "Ilica 1" || cleaned.Creditor.Town != "10000 Zagreb" {
+		t.Fatalf("unexpected creditor: %+v", cleaned.Creditor)
+	}
+}
@@ -0,0 +1,151 @@
+package validate
+
+import (
+	"strings"
+	"time"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// MNB QR transfer types: a credit transfer initiated by the payer (HCT) or
+// a request to pay presented by the payee (RTP).
+const (
+	MNBTransferHCT = "HCT"
+	MNBTransferRTP = "RTP"
+)
+
+// cleanValidUntil parses an RFC 3339 timestamp. The MNB format carries the
+// UTC offset as a single digit of whole hours.
+func cleanValidUntil(v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return "", fieldErrorf("valid_until", CodeRequired, "valid_until is required")
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return "", fieldErrorf("valid_until", CodeInvalid, "invalid valid_until")
+	}
+	_, offset := t.Zone()
+	if offset%3600 != 0 || offset < -9*3600 || offset > 9*3600 {
+		return "", fieldErrorf("valid_until", CodeInvalid, "invalid valid_until")
+	}
+	return t.Format(time.RFC3339), nil
+}
+
+func cleanHUMNB(in Input) (*Clean, error) {
+	const scheme = SchemeHUMNB
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"rf_from_invoice", in.RFFromInvoice},
+		fieldValue{"information", in.Information},
+		fieldValue{"due_date", in.DueDate},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_street", in.CreditorStreet},
+		fieldValue{"creditor_building_number", in.CreditorBuildingNumber},
+		fieldValue{"creditor_postal_code", in.CreditorPostalCode},
+		fieldValue{"creditor_town", in.CreditorTown},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+
+	transferType := strings.ToUpper(strings.TrimSpace(in.TransferType))
+	if transferType == "" {
+		transferType = MNBTransferHCT
+	}
+	if transferType != MNBTransferHCT && transferType != MNBTransferRTP {
+		errs.add(fieldErrorf("transfer_type", CodeInvalid, "invalid transfer_type"))
+	}
+	// A request to pay always states the amount.
+	if transferType == MNBTransferRTP && in.OpenAmount {
+		errs.add(fieldErrorf("open_amount", CodeUnsupported, "open_amount is not supported by RTP"))
+	}
+	if cur := strings.ToUpper(strings.TrimSpace(in.Currency)); cur != "" && cur != "HUF" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be HUF"))
+	}
+
+	warnings := []Warning{}
+	text := func(field, v string, max int) string {
+		var err error
+		v, warnings, err = truncateField(field, strings.TrimSpace(oneLine.Replace(v)), max, warnings)
+		errs.add(err)
+		return v
+	}
+
+	name := text("name", in.Name, 70)
+	if name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case ibanCountry(iban) != "HU":
+		errs.add(fieldErrorf("iban", CodeUnsupported, "iban must be a HU IBAN"))
+	}
+	bic := strings.ToUpper(strings.TrimSpace(in.BIC))
+	if !errs.failed("iban") {
+		bic, warnings = checkBIC(in, iban, bic, warnings)
+	}
+	if bic == "" {
+		errs.add(fieldErrorf("bic", CodeRequired, "bic is required"))
+	} else if !reBIC.MatchString(bic) {
+		errs.add(fieldErrorf("bic", CodeInvalid, "invalid bic"))
+	}
+
+	// Forint amounts are whole numbers of up to 12 digits.
+	amtCents, err := parseSchemeAmount(in, "HUF", 999999999999*100)
+	if !errs.add(err) && amtCents%100 != 0 {
+		errs.add(fieldErrorf("amount", CodeInvalid, "amount must be whole forints"))
+	}
+
+	validUntil, err := cleanValidUntil(in.ValidUntil)
+	errs.add(err)
+
+	// The payment situation identifier is optional.
+	purpose := strings.TrimSpace(in.Purpose)
+	if purpose != "" {
+		purpose, warnings, err = checkPurpose(purpose, warnings)
+		errs.add(err)
+		purpose, warnings, err = truncateField("purpose", purpose, 4, warnings)
+		errs.add(err)
+	}
+	ref := text("remittance_reference", in.RemittanceReference, 35)
+	message := text("remittance_text", in.RemittanceText, 70)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "001",
+		Charset:             charset.UTF8,
+		Name:                name,
+		IBAN:                iban,
+		BIC:                 bic,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            "HUF",
+		Purpose:             purpose,
+		RemittanceReference: ref,
+		RemittanceText:      message,
+		TransferType:        transferType,
+		ValidUntil:          validUntil,
+		Transliterations:    []Transliteration{},
+		Warnings:            warnings,
+	}, nil
+}
@@ -0,0 +1,211 @@
+package validate
+
+import "testing"
+
+func TestCleanAndValidate_HUMNB(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+			},
+		},
+		{
+			name: "rtp_with_purpose",
+			in: Input{
+				Scheme:         "hu_mnb",
+				Name:           "Példa Kft.",
+				IBAN:           "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:            "OTPVHUHB",
+				Amount:         "HUF 12500",
+				ValidUntil:     "2026-11-30T18:00:00+01:00",
+				TransferType:   "rtp",
+				Purpose:        "gdds",
+				RemittanceText: "Számla 17",
+			},
+		},
+		{
+			name: "hct_open_amount",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+				OpenAmount: true,
+			},
+		},
+		{
+			name: "rtp_open_amount",
+			in: Input{
+				Scheme:       "hu_mnb",
+				Name:         "Példa Kft.",
+				IBAN:         "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:          "OTPVHUHB",
+				ValidUntil:   "2026-11-30T18:00:00+01:00",
+				TransferType: "RTP",
+				OpenAmount:   true,
+			},
+			wantErr: "open_amount is not supported by RTP",
+		},
+		{
+			name: "transfer_type_invalid",
+			in: Input{
+				Scheme:       "hu_mnb",
+				Name:         "Példa Kft.",
+				IBAN:         "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:          "OTPVHUHB",
+				Amount:       "HUF 12500",
+				ValidUntil:   "2026-11-30T18:00:00+01:00",
+				TransferType: "SCT",
+			},
+			wantErr: "invalid transfer_type",
+		},
+		{
+			name: "fractional_amount",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "12.50",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+			},
+			wantErr: "amount must be whole forints",
+		},
+		{
+			name: "currency_eur",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+				Currency:   "EUR",
+			},
+			wantErr: "currency must be HUF",
+		},
+		{
+			name: "iban_not_hu",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "DE12500105170648489890",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+			},
+			wantErr: "iban must be a HU IBAN",
+		},
+		{
+			name: "bic_required",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+			},
+			wantErr: "bic is required",
+		},
+		{
+			name: "valid_until_required",
+			in: Input{
+				Scheme: "hu_mnb",
+				Name:   "Példa Kft.",
+				IBAN:   "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:    "OTPVHUHB",
+				Amount: "HUF 12500",
+			},
+			wantErr: "valid_until is required",
+		},
+		{
+			name: "valid_until_no_zone",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00",
+			},
+			wantErr: "invalid valid_until",
+		},
+		{
+			name: "valid_until_half_hour",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+05:30",
+			},
+			wantErr: "invalid valid_until",
+		},
+		{
+			name: "due_date_not_supported",
+			in: Input{
+				Scheme:     "hu_mnb",
+				Name:       "Példa Kft.",
+				IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+				BIC:        "OTPVHUHB",
+				Amount:     "HUF 12500",
+				ValidUntil: "2026-11-30T18:00:00+01:00",
+				DueDate:    "2026-11-30",
+			},
+			wantErr: "due_date is not supported by hu_mnb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_HUMNBDefaults(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:     "hu_mnb",
+		Name:       "Példa Kft.",
+		IBAN:       "HU42 1177 3016 1111 1018 0000 0000",
+		BIC:        "OTPVHUHB",
+		Amount:     "HUF 12500",
+		ValidUntil: "2026-11-30T18:00:00+01:00",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.TransferType != MNBTransferHCT || cleaned.Currency != "HUF" || cleaned.AmountCents != 1250000 || cleaned.Purpose != "" {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+
+	_, err = CleanAndValidate(Input{
+		Scheme:         "cz_spd",
+		Name:           "Example s.r.o.",
+		IBAN:           "CZ65 0800 0000 1920 0014 5399",
+		Amount:         "450,00",
+		VariableSymbol: "1234567890",
+		DueDate:        "2026-11-30",
+		ValidUntil:     "2026-11-30T18:00:00+01:00",
+	})
+	if err == nil || err.Error() != "valid_until is not supported by cz_spd" {
+		t.Fatalf("expected cz_spd to reject valid_until, got %v", err)
+	}
+}
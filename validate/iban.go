@@ -1,22 +1,75 @@
 package validate
 
+import (
+	"slices"
+	"strings"
+)
+
+// ValidIBAN reports whether iban passes CheckIBAN.
 func ValidIBAN(iban string) bool {
-	// Basic sanitization
-	if len(iban) < 15 || len(iban) > 34 {
-		return false
+	return CheckIBAN(iban) == nil
+}
+
+const ibanChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// maxIBANSuggestions caps the list SuggestIBANs returns.
+const maxIBANSuggestions = 5
+
+// SuggestIBANs lists up to maxIBANSuggestions IBANs one typo away from iban
+// that pass CheckIBAN: two swapped neighbouring characters first, then one
+// changed character, each group sorted. The country code is kept. Valid
+// IBANs and input with characters outside A-Z and 0-9 get no suggestions.
+func SuggestIBANs(iban string) []string {
+	iban = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(iban), " ", ""))
+	if len(iban) < 5 || ValidIBAN(iban) {
+		return nil
 	}
-	for _, r := range iban {
-		if !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
-			return false
+	for i := 0; i < len(iban); i++ {
+		if strings.IndexByte(ibanChars, iban[i]) < 0 {
+			return nil
 		}
 	}
 
-	// Move first 4 chars to the end
-	rearranged := iban[4:] + iban[:4]
+	var swaps, subs []string
+	seen := map[string]bool{}
+	try := func(dst *[]string, b []byte) {
+		if c := string(b); !seen[c] && ValidIBAN(c) {
+			seen[c] = true
+			*dst = append(*dst, c)
+		}
+	}
+	b := []byte(iban)
+	for i := 2; i+1 < len(b); i++ {
+		if b[i] != b[i+1] {
+			b[i], b[i+1] = b[i+1], b[i]
+			try(&swaps, b)
+			b[i], b[i+1] = b[i+1], b[i]
+		}
+	}
+	for i := 2; i < len(b); i++ {
+		orig := b[i]
+		for j := 0; j < len(ibanChars); j++ {
+			if ibanChars[j] != orig {
+				b[i] = ibanChars[j]
+				try(&subs, b)
+			}
+		}
+		b[i] = orig
+	}
+	slices.Sort(swaps)
+	slices.Sort(subs)
+	out := append(swaps, subs...)
+	if len(out) > maxIBANSuggestions {
+		out = out[:maxIBANSuggestions]
+	}
+	return out
+}
 
-	// Convert letters to numbers (A=10..Z=35), compute mod 97
+// mod97 converts letters to numbers (A=10..Z=35) and computes the
+// ISO 7064 MOD 97-10 remainder. It reports false for other characters.
+func mod97(s string) (int, bool) {
 	mod := 0
-	for _, r := range rearranged {
+	for _, r := range s {
 		if r >= '0' && r <= '9' {
 			mod = (mod*10 + int(r-'0')) % 97
 			continue
@@ -28,8 +81,7 @@ func ValidIBAN(iban string) bool {
 			mod = (mod*10 + (val % 10)) % 97
 			continue
 		}
-		return false
+		return 0, false
 	}
-
-	return mod == 1
+	return mod, true
 }
@@ -0,0 +1,48 @@
+package validate
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggestIBANs(t *testing.T) {
+	const valid = "DE12500105170648489890"
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"transposition", "DE12500105170648489809"},
+		{"substitution", "DE12500105170648489891"},
+		{"check_digits_swapped", "DE21500105170648489890"},
+		{"spaces_and_case", "de12 5001 0517 0648 4898 91"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SuggestIBANs(tt.in)
+			if !slices.Contains(got, valid) {
+				t.Fatalf("SuggestIBANs(%q) = %v, want it to contain %s", tt.in, got, valid)
+			}
+			for _, s := range got {
+				if !ValidIBAN(s) {
+					t.Fatalf("suggestion %s is not a valid IBAN", s)
+				}
+			}
+		})
+	}
+
+	// Transpositions come first.
+	if got := SuggestIBANs("DE12500105170648489809"); got[0] != valid {
+		t.Fatalf("expected transposition first, got %v", got)
+	}
+	// A French BBAN allows letters in the account number, so this typo has
+	// six valid neighbours; the first five in sort order are returned.
+	want := []string{"FR1420001010050500013M02607", "FR1420041010050500013M02606", "FR142004101005050001LM02607", "FR142004101005050N013M02607", "FR14200410100505C0013M02607"}
+	if got := SuggestIBANs("FR1420041010050500013M02607"); !slices.Equal(got, want) || len(got) != maxIBANSuggestions {
+		t.Fatalf("expected %d sorted suggestions %v, got %v", maxIBANSuggestions, want, got)
+	}
+	for _, in := range []string{valid, "DE1250010517064848989", "DE12500105170648489-90", ""} {
+		if got := SuggestIBANs(in); got != nil {
+			t.Fatalf("SuggestIBANs(%q) = %v, want none", in, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestIBANRegistryConsistent(t *testing.T) {
+	for country, f := range ibanRegistry {
+		sum := 0
+		for _, m := range regexp.MustCompile(`([0-9]+)!`).FindAllStringSubmatch(f.bban, -1) {
+			n, _ := strconv.Atoi(m[1])
+			sum += n
+		}
+		if sum+4 != f.length {
+			t.Fatalf("%s: bban %q gives length %d, registry says %d", country, f.bban, sum+4, f.length)
+		}
+	}
+}
+
+func TestCheckIBAN(t *testing.T) {
+	valid := []string{
+		"DE12500105170648489890",
+		"GB82WEST12345698765432",
+		"FR1420041010050500013M02606",
+		"NL91ABNA0417164300",
+		"BE68539007547034",
+		"AT611904300234573201",
+		"IT60X0542811101000000123456",
+		"ES9121000418450200051332",
+		"NO9386011117947",
+		"MT84MALT011000012345MTLCAST001S",
+		"MU17BOMM0101101030300200000MUR",
+		"BR1800360305000010009795493C1",
+	}
+	for _, iban := range valid {
+		if err := CheckIBAN(iban); err != nil {
+			t.Fatalf("CheckIBAN(%q): %v", iban, err)
+		}
+	}
+
+	tests := []struct {
+		iban string
+		want string
+	}{
+		{"DE605001051706484898901", "iban length invalid for country DE"},
+		{"FR43A0041010050500013M02606", "iban bban format invalid"},
+		{"DE13500105170648489890", "invalid iban"},
+		{"XX12500105170648489890", "invalid iban"},
+		{"DEAB500105170648489890", "invalid iban"},
+		{"de12500105170648489890", "invalid iban"},
+		{"DE12", "invalid iban"},
+	}
+	for _, tt := range tests {
+		if err := CheckIBAN(tt.iban); err == nil || err.Error() != tt.want {
+			t.Fatalf("CheckIBAN(%q): expected %q, got %v", tt.iban, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// reOCRLabel matches a leading field label such as "IBAN:" or "BIC/SWIFT".
+var reOCRLabel = regexp.MustCompile(`^(?:IBAN|BIC|SWIFT)(?:\s*/\s*(?:IBAN|BIC|SWIFT))?(?:\s*[:#]\s*|\s+)`)
+
+// ocrSwap maps the characters OCR confuses most often to each other.
+var ocrSwap = map[byte]byte{'O': '0', '0': 'O', 'I': '1', '1': 'I'}
+
+// maxOCRSwaps caps the ambiguous characters tried per value (2^n candidates).
+const maxOCRSwaps = 12
+
+// ocrTooAmbiguous is the match count correctOCR reports when a value has
+// more than maxOCRSwaps ambiguous characters and no reading was tried.
+const ocrTooAmbiguous = -1
+
+var ibanLenientOCR atomic.Bool
+
+// SetIBANLenientOCR allows requests to ask for OCR cleanup of iban and bic
+// with iban_format "ocr_lenient": field labels, dashes and dots are dropped
+// and O/0 and I/1 mix-ups are corrected when exactly one reading passes the
+// checks.
+func SetIBANLenientOCR(enabled bool) {
+	ibanLenientOCR.Store(enabled)
+}
+
+// ibanFormatLenient reports whether iban_format asks for the OCR cleanup.
+// "ocr_lenient" is only supported while SetIBANLenientOCR is on.
+func ibanFormatLenient(format string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "strict":
+		return false, nil
+	case "ocr_lenient":
+		if !ibanLenientOCR.Load() {
+			return false, fieldErrorf("iban_format", CodeUnsupported, "unsupported iban_format")
+		}
+		return true, nil
+	default:
+		return false, fieldErrorf("iban_format", CodeUnsupported, "unsupported iban_format")
+	}
+}
+
+// correctAccountOCR applies the lenient IBAN/BIC cleanup to in and reports
+// each changed value as an "ocr_corrected" warning. Values with more than
+// one valid reading, or too many ambiguous characters to try, are left as
+// sent and reported as "ocr_ambiguous".
+func correctAccountOCR(in Input) (Input, []Warning) {
+	var warnings []Warning
+	for _, f := range []struct {
+		name    string
+		v       *string
+		classes func([]byte) []byte
+		valid   func(string) bool
+	}{
+		{"iban", &in.IBAN, ibanOCRClasses, ValidIBAN},
+		{"bic", &in.BIC, bicOCRClasses, ValidBIC},
+	} {
+		raw := *f.v
+		plain := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(raw), " ", ""))
+		if plain == "" || f.valid(plain) {
+			continue
+		}
+		corrected, matches := correctOCR(raw, f.classes, f.valid)
+		if matches == ocrTooAmbiguous {
+			warnings = append(warnings, Warning{
+				Field:   f.name,
+				Code:    CodeOCRAmbiguous,
+				Message: fmt.Sprintf("%s %q not corrected: more than %d ambiguous characters", f.name, raw, maxOCRSwaps),
+			})
+			continue
+		}
+		if matches > 1 {
+			warnings = append(warnings, Warning{
+				Field:   f.name,
+				Code:    CodeOCRAmbiguous,
+				Message: fmt.Sprintf("%s %q not corrected: %d readings are valid", f.name, raw, matches),
+			})
+			continue
+		}
+		if matches == 0 || corrected == plain {
+			continue
+		}
+		*f.v = corrected
+		warnings = append(warnings, Warning{
+			Field:   f.name,
+			Code:    "ocr_corrected",
+			Message: fmt.Sprintf("%s %q read as %s", f.name, raw, corrected),
+		})
+	}
+	return in, warnings
+}
+
+// correctOCR strips a field label and separators from v and tries every
+// O/0 and I/1 reading. Positions that classes marks as letters ('a') or
+// digits ('n') are read that way up front; only the others are tried both
+// ways, and nothing is tried when classes finds no structure. It returns the number of readings that pass valid and, when there
+// is exactly one, that reading.
+func correctOCR(v string, classes func([]byte) []byte, valid func(string) bool) (string, int) {
+	v = reOCRLabel.ReplaceAllString(strings.ToUpper(strings.TrimSpace(v)), "")
+	b := make([]byte, 0, len(v))
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		case c == ' ', c == '\t', c == '-', c == '.':
+		default:
+			return "", 0
+		}
+	}
+
+	class := classes(b)
+	if class == nil {
+		return "", 0
+	}
+	var pos []int
+	for i, c := range b {
+		if _, ok := ocrSwap[c]; !ok {
+			continue
+		}
+		if class[i] == 'c' {
+			pos = append(pos, i)
+			continue
+		}
+		b[i] = ocrRead(c, class[i])
+	}
+	if len(pos) > maxOCRSwaps {
+		return "", ocrTooAmbiguous
+	}
+
+	var found string
+	matches := 0
+	cand := make([]byte, len(b))
+	for m := 0; m < 1<<len(pos); m++ {
+		copy(cand, b)
+		for k, p := range pos {
+			if m&(1<<k) != 0 {
+				cand[p] = ocrSwap[cand[p]]
+			}
+		}
+		if valid(string(cand)) {
+			matches++
+			found = string(cand)
+		}
+	}
+	if matches != 1 {
+		return "", matches
+	}
+	return found, 1
+}
+
+// ocrRead returns the reading of the ambiguous character c that fits class:
+// a digit for 'n', a letter for 'a' and c itself otherwise.
+func ocrRead(c, class byte) byte {
+	switch {
+	case class == 'n' && (c == 'O' || c == 'I'), class == 'a' && (c == '0' || c == '1'):
+		return ocrSwap[c]
+	}
+	return c
+}
+
+// ibanOCRClasses returns the character class of each position of iban: the
+// country code is letters, the check digits are digits and the BBAN follows
+// the registry structure. It returns nil for an unknown country or length,
+// which no reading can fix.
+func ibanOCRClasses(iban []byte) []byte {
+	if len(iban) < 4 {
+		return nil
+	}
+	f, ok := ibanRegistry[string([]byte{ocrRead(iban[0], 'a'), ocrRead(iban[1], 'a')})]
+	if !ok || len(iban) != f.length {
+		return nil
+	}
+	class := []byte("aann")
+	for _, m := range reRegistryPart.FindAllStringSubmatch(f.bban, -1) {
+		n, _ := strconv.Atoi(m[1])
+		class = append(class, bytes.Repeat([]byte(m[2]), n)...)
+	}
+	return class
+}
+
+// bicOCRClasses returns the character class of each position of bic: the
+// bank and country codes are letters, location and branch alphanumeric.
+// It returns nil for a value that is not 8 or 11 characters long.
+func bicOCRClasses(bic []byte) []byte {
+	switch len(bic) {
+	case 8:
+		return []byte("aaaaaacc")
+	case 11:
+		return []byte("aaaaaaccccc")
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package validate
+
+import "testing"
+
+func TestCorrectOCR(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		classes func([]byte) []byte
+		valid   func(string) bool
+		want    string
+		n       int
+	}{
+		{"iban_label_and_dashes", "IBAN: DE12-5001-0517-0648-4898-90", ibanOCRClasses, ValidIBAN, "DE12500105170648489890", 1},
+		{"iban_dots", "DE12.5001.0517.0648.4898.90", ibanOCRClasses, ValidIBAN, "DE12500105170648489890", 1},
+		{"iban_letter_o", "DE12 5OO1 O517 O648 4898 9O", ibanOCRClasses, ValidIBAN, "DE12500105170648489890", 1},
+		{"iban_letter_i", "DE12 5001 05I7 0648 4898 90", ibanOCRClasses, ValidIBAN, "DE12500105170648489890", 1},
+		{"iban_digit_country", "0E12500105170648489890", ibanOCRClasses, ValidIBAN, "", 0},
+		{"iban_wrong_digit", "DE12500105170648489891", ibanOCRClasses, ValidIBAN, "", 0},
+		{"iban_many_letter_o", "DE02 1001 OO10 OOO6 82O1 O1", ibanOCRClasses, ValidIBAN, "DE02100100100006820101", 1},
+		{"iban_alnum_bban", "GB82 WEST 1234 5698 7654 32", ibanOCRClasses, ValidIBAN, "GB82WEST12345698765432", 1},
+		{"iban_letter_in_bban_digits", "GB82 WE5T I234 5698 7654 32", ibanOCRClasses, ValidIBAN, "", 0},
+		{"iban_too_many_alnum", "MT84 MALT 0110 0010 1010 1010 10AB CDE", ibanOCRClasses, ValidIBAN, "", ocrTooAmbiguous},
+		{"iban_bad_char", "DE12_5001_0517_0648_4898_90", ibanOCRClasses, ValidIBAN, "", 0},
+		{"bic_label", "BIC/SWIFT: INGD-DEFF-XXX", bicOCRClasses, ValidBIC, "INGDDEFFXXX", 1},
+		{"bic_digit_in_bank_code", "C0BADEFF", bicOCRClasses, ValidBIC, "COBADEFF", 1},
+		{"bic_ambiguous_location", "INGD-DEFF-1XX", bicOCRClasses, ValidBIC, "", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, n := correctOCR(tt.in, tt.classes, tt.valid)
+			if got != tt.want || n != tt.n {
+				t.Fatalf("correctOCR(%q) = %q, %d; want %q, %d", tt.in, got, n, tt.want, tt.n)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_IBANLenientOCR(t *testing.T) {
+	in := Input{
+		Name:   "Example GmbH",
+		IBAN:   "IBAN: DE12 5OO1 O517 O648 4898 9O",
+		BIC:    "INGD-DEFF-XXX",
+		Amount: "1",
+	}
+	SetIBANLenientOCR(true)
+	defer SetIBANLenientOCR(false)
+	if _, err := CleanAndValidate(in); err == nil || err.Error() != "invalid iban" {
+		t.Fatalf("expected invalid iban without iban_format, got %v", err)
+	}
+
+	in.IBANFormat = "ocr_lenient"
+	cleaned, err := CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.IBAN != "DE12500105170648489890" || cleaned.BIC != "INGDDEFFXXX" {
+		t.Fatalf("unexpected iban/bic: %s %s", cleaned.IBAN, cleaned.BIC)
+	}
+	if len(cleaned.Warnings) != 2 || cleaned.Warnings[0].Field != "iban" || cleaned.Warnings[0].Code != "ocr_corrected" ||
+		cleaned.Warnings[1].Field != "bic" {
+		t.Fatalf("unexpected warnings: %+v", cleaned.Warnings)
+	}
+	if cleaned.Warnings[0].Message != `iban "IBAN: DE12 5OO1 O517 O648 4898 9O" read as DE12500105170648489890` {
+		t.Fatalf("unexpected message: %s", cleaned.Warnings[0].Message)
+	}
+
+	SetIBANLenientOCR(false)
+	_, err = CleanAndValidate(in)
+	if fes := FieldErrors(err); len(fes) != 1 || fes[0].Field != "iban_format" || fes[0].Code != CodeUnsupported {
+		t.Fatalf("expected unsupported iban_format while the switch is off, got %v", err)
+	}
+}
+
+func TestCleanAndValidate_IBANLenientOCRAmbiguous(t *testing.T) {
+	SetIBANLenientOCR(true)
+	defer SetIBANLenientOCR(false)
+	in := Input{Name: "Example GmbH", IBAN: "DE12500105170648489890", BIC: "INGD-DEFF-1XX", IBANFormat: "ocr_lenient", Amount: "1"}
+	_, err := CleanAndValidate(in)
+	if err == nil || err.Error() != "invalid bic" {
+		t.Fatalf("expected ambiguous bic to stay invalid, got %v", err)
+	}
+	if fes := FieldErrors(err); len(fes) != 2 || fes[1].Field != "bic" || fes[1].Code != CodeOCRAmbiguous {
+		t.Fatalf("expected ocr_ambiguous field error, got %+v", fes)
+	}
+	in, warnings := correctAccountOCR(in)
+	if in.BIC != "INGD-DEFF-1XX" || len(warnings) != 1 || warnings[0].Code != "ocr_ambiguous" ||
+		warnings[0].Message != `bic "INGD-DEFF-1XX" not corrected: 2 readings are valid` {
+		t.Fatalf("unexpected result: %s %+v", in.BIC, warnings)
+	}
+}
+
+func TestCleanAndValidate_IBANLenientOCRManyAmbiguous(t *testing.T) {
+	SetIBANLenientOCR(true)
+	defer SetIBANLenientOCR(false)
+	// Thirteen 0/1 characters, all in numeric BBAN positions.
+	cleaned, err := CleanAndValidate(Input{
+		Name:       "Example GmbH",
+		IBAN:       "DE02 1001 OO10 0006 8201 01",
+		BIC:        "INGDDEFFXXX",
+		IBANFormat: "ocr_lenient",
+		Amount:     "1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.IBAN != "DE02100100100006820101" || len(cleaned.Warnings) == 0 || cleaned.Warnings[0].Code != "ocr_corrected" {
+		t.Fatalf("unexpected result: %s %+v", cleaned.IBAN, cleaned.Warnings)
+	}
+
+	_, err = CleanAndValidate(Input{
+		Name:       "Example GmbH",
+		IBAN:       "MT84 MALT 0110 0010 1010 1010 10AB CDE",
+		BIC:        "MALTMTMT",
+		IBANFormat: "ocr_lenient",
+		Amount:     "1",
+	})
+	fes := FieldErrors(err)
+	if len(fes) != 2 || fes[1].Field != "iban" || fes[1].Code != CodeOCRAmbiguous ||
+		fes[1].Message != `iban "MT84 MALT 0110 0010 1010 1010 10AB CDE" not corrected: more than 12 ambiguous characters` {
+		t.Fatalf("expected ocr_ambiguous field error, got %+v", fes)
+	}
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	_ "embed"
+	"strings"
+	"sync/atomic"
+)
+
+// purpose_codes.tsv holds the ISO 20022 ExternalPurpose1Code catalogue,
+// one "CODE<TAB>Name" entry per line, sorted by code.
+//
+//go:embed purpose_codes.tsv
+var purposeCodesTSV string
+
+// PurposeCode is one entry of the ISO 20022 ExternalPurpose1Code list.
+type PurposeCode struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+var purposeCodes, purposeIndex = parsePurposeCodes(purposeCodesTSV)
+
+var purposeLenient atomic.Bool
+
+// SetPurposeLenient makes unknown purpose codes a warning instead of an error.
+func SetPurposeLenient(enabled bool) {
+	purposeLenient.Store(enabled)
+}
+
+// PurposeCodes returns the embedded catalogue sorted by code.
+func PurposeCodes() []PurposeCode {
+	out := make([]PurposeCode, len(purposeCodes))
+	copy(out, purposeCodes)
+	return out
+}
+
+// KnownPurposeCode reports whether code is in the ISO 20022 catalogue.
+// The check is case-sensitive; codes are upper case.
+func KnownPurposeCode(code string) bool {
+	_, ok := purposeIndex[code]
+	return ok
+}
+
+func parsePurposeCodes(raw string) ([]PurposeCode, map[string]string) {
+	var list []PurposeCode
+	index := make(map[string]string)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		code, name, _ := strings.Cut(line, "\t")
+		list = append(list, PurposeCode{Code: code, Name: strings.TrimSpace(name)})
+		index[code] = strings.TrimSpace(name)
+	}
+	return list, index
+}
@@ -0,0 +1,62 @@
+package validate
+
+import "testing"
+
+func TestPurposeCodes(t *testing.T) {
+	codes := PurposeCodes()
+	if len(codes) < 300 {
+		t.Fatalf("expected the full catalogue, got %d codes", len(codes))
+	}
+	for i, c := range codes {
+		if len(c.Code) != 4 || c.Name == "" {
+			t.Fatalf("malformed entry: %+v", c)
+		}
+		if i > 0 && codes[i-1].Code >= c.Code {
+			t.Fatalf("catalogue not sorted at %q", c.Code)
+		}
+	}
+	for _, code := range []string{"GDDS", "SALA", "PENS", "TAXS", "SUPP", "CHAR", "RENT"} {
+		if !KnownPurposeCode(code) {
+			t.Fatalf("expected %s to be known", code)
+		}
+	}
+	for _, code := range []string{"XXXX", "GD1", "gdds", ""} {
+		if KnownPurposeCode(code) {
+			t.Fatalf("expected %q to be unknown", code)
+		}
+	}
+}
+
+func TestCleanAndValidate_PurposeLenient(t *testing.T) {
+	in := Input{
+		Name:    "Example GmbH",
+		IBAN:    "DE12500105170648489890",
+		BIC:     "INGDDEFFXXX",
+		Amount:  "1",
+		Purpose: "xxxxy",
+	}
+	_, err := CleanAndValidate(in)
+	if err == nil || err.Error() != "unknown purpose code" {
+		t.Fatalf("expected unknown purpose code, got %v", err)
+	}
+
+	SetPurposeLenient(true)
+	defer SetPurposeLenient(false)
+	cleaned, err := CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.Purpose != "XXXX" {
+		t.Fatalf("expected purpose truncated to XXXX, got %q", cleaned.Purpose)
+	}
+	if len(cleaned.Warnings) != 2 || cleaned.Warnings[0].Field != "purpose" || cleaned.Warnings[0].Code != "unknown_purpose_code" ||
+		cleaned.Warnings[1].Code != "truncated" {
+		t.Fatalf("unexpected warnings: %+v", cleaned.Warnings)
+	}
+
+	in.Purpose = "GDDS"
+	cleaned, err = CleanAndValidate(in)
+	if err != nil || len(cleaned.Warnings) != 0 {
+		t.Fatalf("known code should not warn: %v %+v", err, cleaned)
+	}
+}
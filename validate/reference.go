@@ -0,0 +1,177 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Values of Input.ReferenceType: national remittance references with
+// check digits that are validated and normalized instead of passed
+// through as free text.
+const (
+	RefTypeRF    = "rf"
+	RefTypeBEOGM = "be_ogm"
+	RefTypeNOKID = "no_kid"
+	RefTypeDKFIK = "dk_fik"
+	RefTypeFI    = "fi"
+)
+
+// NormalizeReference checks ref against the rules of refType and returns
+// its canonical form:
+//
+//	rf      RF18539007547034 (ISO 11649, mod 97)
+//	be_ogm  +++123/4567/89002+++ (12 digits, mod 97 of the first ten)
+//	no_kid  2-25 digits, mod 10 (Luhn) or mod 11 check digit ("-" for 10)
+//	dk_fik  +71<15 digits (payment ID with a mod 10 check digit); the
+//	        "+creditor number<" part of a FIK line is rejected, the IBAN
+//	        names the creditor
+//	fi      4-20 digits with the 7-3-1 check digit, leading zeros dropped
+func NormalizeReference(refType, ref string) (string, error) {
+	refType = strings.ToLower(strings.TrimSpace(refType))
+	compact := strings.ToUpper(strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, ref))
+
+	switch refType {
+	case RefTypeRF:
+		if !ValidRF(compact) {
+			return "", fieldErrorf("remittance_reference", CodeInvalidCheckDigit, "invalid creditor reference")
+		}
+		return compact, nil
+	case RefTypeBEOGM:
+		digits := strings.Trim(strings.ReplaceAll(compact, "/", ""), "+*")
+		if len(digits) != 12 || !isDigits(digits) {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid be_ogm reference")
+		}
+		check := mod97Digits(digits[:10])
+		if check == 0 {
+			check = 97
+		}
+		if fmt.Sprintf("%02d", check) != digits[10:] {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid be_ogm reference")
+		}
+		return "+++" + digits[:3] + "/" + digits[3:7] + "/" + digits[7:] + "+++", nil
+	case RefTypeNOKID:
+		if len(compact) < 2 || len(compact) > 25 {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid no_kid reference")
+		}
+		body, check := compact[:len(compact)-1], compact[len(compact)-1:]
+		if !isDigits(body) {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid no_kid reference")
+		}
+		if check != luhnCheckDigit(body) && check != kidMod11CheckDigit(body) {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid no_kid reference")
+		}
+		return compact, nil
+	case RefTypeDKFIK:
+		id := strings.TrimPrefix(compact, "+71<")
+		if strings.IndexByte(id, '+') > 0 {
+			return "", fieldErrorf("remittance_reference", CodeUnsupported, "dk_fik reference must not include the creditor number")
+		}
+		if len(id) != 15 || !isDigits(id) || luhnCheckDigit(id[:14]) != id[14:] {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid dk_fik reference")
+		}
+		return "+71<" + id, nil
+	case RefTypeFI:
+		digits := strings.TrimLeft(compact, "0")
+		if !ValidFIReference(digits) {
+			return "", fieldErrorf("remittance_reference", CodeInvalid, "invalid fi reference")
+		}
+		return digits, nil
+	default:
+		return "", fieldErrorf("reference_type", CodeUnsupported, "unsupported reference_type")
+	}
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func mod97Digits(digits string) int {
+	mod := 0
+	for i := 0; i < len(digits); i++ {
+		mod = (mod*10 + int(digits[i]-'0')) % 97
+	}
+	return mod
+}
+
+// luhnCheckDigit returns the mod 10 (Luhn) check digit for body.
+func luhnCheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		d := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10)
+}
+
+// kidMod11CheckDigit returns the KID mod 11 check digit for body: weights
+// 2-7 repeating from the right, "-" when the result is 10.
+func kidMod11CheckDigit(body string) string {
+	sum := 0
+	for i := 0; i < len(body); i++ {
+		sum += int(body[len(body)-1-i]-'0') * (2 + i%6)
+	}
+	switch k := 11 - sum%11; k {
+	case 11:
+		return "0"
+	case 10:
+		return "-"
+	default:
+		return fmt.Sprintf("%d", k)
+	}
+}
+
+// cleanReference resolves remittance_reference, rf_from_invoice and
+// reference_type into the final reference and its type. Without a type,
+// anything that claims to be RF is still checked strictly.
+func cleanReference(in Input, ref string) (string, string, error) {
+	refType := strings.ToLower(strings.TrimSpace(in.ReferenceType))
+	switch refType {
+	case "", RefTypeRF, RefTypeBEOGM, RefTypeNOKID, RefTypeDKFIK, RefTypeFI:
+	default:
+		return "", "", fieldErrorf("reference_type", CodeUnsupported, "unsupported reference_type")
+	}
+
+	if rfFrom := strings.TrimSpace(in.RFFromInvoice); rfFrom != "" {
+		if ref != "" {
+			return "", "", fieldErrorf("rf_from_invoice", CodeConflict, "remittance_reference and rf_from_invoice are mutually exclusive")
+		}
+		if refType != "" && refType != RefTypeRF {
+			return "", "", fieldErrorf("reference_type", CodeConflict, "rf_from_invoice requires reference_type rf")
+		}
+		rf, err := BuildRF(rfFrom)
+		return rf, refType, err
+	}
+	if refType != "" {
+		if ref == "" {
+			return "", "", fieldErrorf("remittance_reference", CodeRequired, "reference_type requires remittance_reference")
+		}
+		ref, err := NormalizeReference(refType, ref)
+		return ref, refType, err
+	}
+	if looksLikeRF(ref) {
+		ref = strings.ToUpper(strings.ReplaceAll(ref, " ", ""))
+		if !ValidRF(ref) {
+			return "", "", fieldErrorf("remittance_reference", CodeInvalidCheckDigit, "invalid creditor reference")
+		}
+	}
+	return ref, "", nil
+}
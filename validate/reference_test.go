@@ -0,0 +1,96 @@
+package validate
+
+import "testing"
+
+func TestNormalizeReference(t *testing.T) {
+	tests := []struct {
+		refType string
+		in      string
+		want    string
+		wantErr string
+	}{
+		{RefTypeRF, "rf18 5390 0754 7034", "RF18539007547034", ""},
+		{RefTypeRF, "RF19539007547034", "", "invalid creditor reference"},
+		{RefTypeBEOGM, "+++123/4567/89002+++", "+++123/4567/89002+++", ""},
+		{RefTypeBEOGM, "***123/4567/89002***", "+++123/4567/89002+++", ""},
+		{RefTypeBEOGM, "123456789002", "+++123/4567/89002+++", ""},
+		{RefTypeBEOGM, "+++123/4567/89003+++", "", "invalid be_ogm reference"},
+		{RefTypeBEOGM, "12345678900", "", "invalid be_ogm reference"},
+		{RefTypeNOKID, "1234566", "1234566", ""},
+		{RefTypeNOKID, "1234560", "1234560", ""},
+		{RefTypeNOKID, "100008-", "100008-", ""},
+		{RefTypeNOKID, "1234567", "", "invalid no_kid reference"},
+		{RefTypeNOKID, "1", "", "invalid no_kid reference"},
+		{RefTypeDKFIK, "+71<000000012345674", "+71<000000012345674", ""},
+		{RefTypeDKFIK, "000000012345674", "+71<000000012345674", ""},
+		{RefTypeDKFIK, "+71<000000012345675", "", "invalid dk_fik reference"},
+		{RefTypeDKFIK, "+73<000000012345674", "", "invalid dk_fik reference"},
+		{RefTypeDKFIK, "+71<000000012345674+12345678<", "", "dk_fik reference must not include the creditor number"},
+		{RefTypeDKFIK, "000000012345674+12345678", "", "dk_fik reference must not include the creditor number"},
+		{RefTypeFI, "00 1234 5672", "12345672", ""},
+		{RefTypeFI, "12345671", "", "invalid fi reference"},
+		{"se_ocr", "1234", "", "unsupported reference_type"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeReference(tt.refType, tt.in)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("NormalizeReference(%q, %q): expected %q, got %q, %v", tt.refType, tt.in, tt.wantErr, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Fatalf("NormalizeReference(%q, %q)=%q, %v want %q", tt.refType, tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestCleanAndValidate_ReferenceType(t *testing.T) {
+	tests := []struct {
+		name     string
+		mod      func(in *Input)
+		wantRef  string
+		wantType string
+		wantErr  string
+	}{
+		{name: "untyped", mod: func(in *Input) { in.RemittanceReference = "INV-17" }, wantRef: "INV-17"},
+		{name: "be_ogm", mod: func(in *Input) { in.ReferenceType = "BE_OGM"; in.RemittanceReference = "123/4567/89002" }, wantRef: "+++123/4567/89002+++", wantType: "be_ogm"},
+		{name: "rf_from_invoice", mod: func(in *Input) { in.ReferenceType = "rf"; in.RFFromInvoice = "5390 0754 7034" }, wantRef: "RF18539007547034", wantType: "rf"},
+		{name: "bad_check_digit", mod: func(in *Input) { in.ReferenceType = "no_kid"; in.RemittanceReference = "1234567" }, wantErr: "invalid no_kid reference"},
+		{name: "missing_reference", mod: func(in *Input) { in.ReferenceType = "fi" }, wantErr: "reference_type requires remittance_reference"},
+		{name: "rf_from_invoice_wrong_type", mod: func(in *Input) { in.ReferenceType = "fi"; in.RFFromInvoice = "5390" }, wantErr: "rf_from_invoice requires reference_type rf"},
+		{name: "unsupported", mod: func(in *Input) { in.ReferenceType = "se_ocr"; in.RemittanceReference = "1234" }, wantErr: "unsupported reference_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := Input{Name: "Example GmbH", IBAN: "DE12500105170648489890", BIC: "INGDDEFFXXX", Amount: "1"}
+			tt.mod(&in)
+			cleaned, err := CleanAndValidate(in)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cleaned.RemittanceReference != tt.wantRef || cleaned.ReferenceType != tt.wantType {
+				t.Fatalf("reference=%q type=%q want %q %q", cleaned.RemittanceReference, cleaned.ReferenceType, tt.wantRef, tt.wantType)
+			}
+		})
+	}
+
+	_, err := CleanAndValidate(Input{
+		Scheme:         "cz_spd",
+		Name:           "Example s.r.o.",
+		IBAN:           "CZ65 0800 0000 1920 0014 5399",
+		Amount:         "450,00",
+		VariableSymbol: "1234567890",
+		DueDate:        "2026-11-30",
+		ReferenceType:  "rf",
+	})
+	if err == nil || err.Error() != "reference_type is not supported by cz_spd" {
+		t.Fatalf("expected cz_spd to reject reference_type, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	reRFPrefix = regexp.MustCompile(`^RF[0-9]{2}`)
+	reRF       = regexp.MustCompile(`^RF[0-9]{2}[A-Z0-9]{1,21}$`)
+)
+
+// ValidRF reports whether ref is an ISO 11649 creditor reference in
+// electronic format (no spaces) with correct mod-97 check digits.
+func ValidRF(ref string) bool {
+	if !reRF.MatchString(ref) {
+		return false
+	}
+	mod, ok := mod97(ref[4:] + ref[:4])
+	return ok && mod == 1
+}
+
+// BuildRF derives an ISO 11649 creditor reference from a raw invoice
+// number. Separators are dropped; up to 21 letters and digits remain.
+func BuildRF(raw string) (string, error) {
+	body := normalizeRFBody(raw)
+	if body == "" || len(body) > 21 {
+		return "", fieldErrorf("rf_from_invoice", CodeInvalid, "invalid rf_from_invoice")
+	}
+	mod, ok := mod97(body + "RF00")
+	if !ok {
+		return "", fieldErrorf("rf_from_invoice", CodeInvalid, "invalid rf_from_invoice")
+	}
+	return fmt.Sprintf("RF%02d%s", 98-mod, body), nil
+}
+
+// looksLikeRF reports whether a remittance reference claims to be an RF
+// creditor reference and should therefore be checked strictly.
+func looksLikeRF(ref string) bool {
+	return reRFPrefix.MatchString(strings.ToUpper(strings.ReplaceAll(ref, " ", "")))
+}
+
+func normalizeRFBody(raw string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(raw) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '/' || r == '.' || r == '_':
+			// common separators in printed invoice numbers
+		default:
+			return ""
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package validate
+
+import "testing"
+
+func TestValidRF(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"RF18539007547034", true},
+		{"RF712348231", true},
+		{"RF19539007547034", false},
+		{"RF18", false},
+		{"RF18539007547034539007547034", false},
+		{"rf18539007547034", false},
+		{"INV-1", false},
+	}
+	for _, tt := range tests {
+		if got := ValidRF(tt.in); got != tt.want {
+			t.Fatalf("ValidRF(%q)=%v want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildRF(t *testing.T) {
+	got, err := BuildRF("5390 0754 7034")
+	if err != nil || got != "RF18539007547034" {
+		t.Fatalf("BuildRF=%q err=%v", got, err)
+	}
+	got, err = BuildRF("inv-2026/0001")
+	if err != nil || !ValidRF(got) || got[4:] != "INV20260001" {
+		t.Fatalf("BuildRF=%q err=%v", got, err)
+	}
+	for _, bad := range []string{"", "---", "INV#1", "1234567890123456789012", "ÄÖÜ"} {
+		if _, err := BuildRF(bad); err == nil {
+			t.Fatalf("BuildRF(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCleanAndValidate_RemittanceReferenceRF(t *testing.T) {
+	base := Input{
+		Name:   "Example GmbH",
+		IBAN:   "DE12500105170648489890",
+		BIC:    "INGDDEFFXXX",
+		Amount: "1",
+	}
+
+	in := base
+	in.RemittanceReference = "rf18 5390 0754 7034"
+	cleaned, err := CleanAndValidate(in)
+	if err != nil || cleaned.RemittanceReference != "RF18539007547034" {
+		t.Fatalf("expected normalized RF, got %+v err=%v", cleaned, err)
+	}
+
+	in.RemittanceReference = "RF19539007547034"
+	if _, err := CleanAndValidate(in); err == nil || err.Error() != "invalid creditor reference" {
+		t.Fatalf("expected invalid creditor reference, got %v", err)
+	}
+
+	in.RemittanceReference = "INV-1"
+	if _, err := CleanAndValidate(in); err != nil {
+		t.Fatalf("free-form reference should pass: %v", err)
+	}
+
+	in = base
+	in.RFFromInvoice = "2026-0001"
+	cleaned, err = CleanAndValidate(in)
+	if err != nil || !ValidRF(cleaned.RemittanceReference) {
+		t.Fatalf("expected generated RF, got %+v err=%v", cleaned, err)
+	}
+
+	in.RemittanceReference = "INV-1"
+	if _, err := CleanAndValidate(in); err == nil {
+		t.Fatalf("expected mutual exclusion error")
+	}
+}
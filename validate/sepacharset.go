@@ -0,0 +1,88 @@
+package validate
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// SEPA charset modes for epc_sct and bezahlcode text fields. The default
+// keeps every character the payload charset can hold.
+const (
+	// SEPACharsetStrict rejects characters outside the SEPA basic Latin set.
+	SEPACharsetStrict = "strict"
+	// SEPACharsetTransliterate replaces them (ü→ue, ß→ss, Cyrillic→Latin).
+	SEPACharsetTransliterate = "transliterate"
+)
+
+// sepaAllowed is the Latin character set of the EPC guidelines that every
+// SEPA bank has to accept: a-z A-Z 0-9 / - ? : ( ) . , ' + and space.
+func sepaAllowed(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("/-?:().,'+ ", r)
+}
+
+// sepaFold holds the replacements that differ from charset.Fallback:
+// German umlauts keep their sound, and common ASCII punctuation maps to
+// the nearest allowed character instead of "?".
+var sepaFold = map[rune]string{
+	'Ä': "Ae", 'Ö': "Oe", 'Ü': "Ue", 'ä': "ae", 'ö': "oe", 'ü': "ue", 'ß': "ss",
+	'&': "+", '_': "-", '"': "'", '`': "'", ';': ",", '!': ".", '*': ".",
+	'<': "(", '>': ")", '[': "(", ']': ")", '{': "(", '}': ")",
+	'\\': "/", '|': "/", '~': "-", '=': "-",
+}
+
+// sepaFallback returns the SEPA basic Latin replacement of r.
+func sepaFallback(r rune) string {
+	if v, ok := sepaFold[r]; ok {
+		return v
+	}
+	var b strings.Builder
+	for _, fr := range charset.Fallback(r) {
+		switch v, ok := sepaFold[fr]; {
+		case sepaAllowed(fr):
+			b.WriteRune(fr)
+		case ok:
+			b.WriteString(v)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// parseSEPACharset normalizes the sepa_charset option.
+func parseSEPACharset(mode string) (string, error) {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case "", SEPACharsetStrict, SEPACharsetTransliterate:
+		return mode, nil
+	default:
+		return "", fieldErrorf("sepa_charset", CodeUnsupported, "unsupported sepa_charset")
+	}
+}
+
+// cleanSEPAText applies a sepa_charset mode to one field. Strict mode
+// names every offending character; transliteration records each change.
+func cleanSEPAText(mode, field, v string, acc []Transliteration) (string, []Transliteration, error) {
+	switch mode {
+	case SEPACharsetStrict:
+		var bad []string
+		seen := map[rune]bool{}
+		for _, r := range v {
+			if !sepaAllowed(r) && !seen[r] {
+				seen[r] = true
+				bad = append(bad, strconv.QuoteRune(r))
+			}
+		}
+		if len(bad) > 0 {
+			return "", acc, fieldErrorf(field, CodeInvalidCharacter, "%s contains characters outside the SEPA character set: %s", field, strings.Join(bad, ", "))
+		}
+	case SEPACharsetTransliterate:
+		v, acc = transliterateWith(field, v, sepaAllowed, sepaFallback, acc)
+	}
+	return v, acc, nil
+}
@@ -0,0 +1,100 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCleanAndValidate_SEPACharset(t *testing.T) {
+	in := Input{
+		Name:           "Müller & Söhne",
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "10",
+		RemittanceText: "Счёт 42; Straße",
+	}
+
+	c, err := CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("default mode: %v", err)
+	}
+	if c.Name != "Müller & Söhne" || len(c.Transliterations) != 0 {
+		t.Fatalf("default mode must keep UTF-8 text, got %q %v", c.Name, c.Transliterations)
+	}
+
+	in.SEPACharset = "strict"
+	_, err = CleanAndValidate(in)
+	if err == nil || err.Error() != `name contains characters outside the SEPA character set: 'ü', '&', 'ö'` {
+		t.Fatalf("expected strict error, got %v", err)
+	}
+
+	in.SEPACharset = "Transliterate"
+	c, err = CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("transliterate: %v", err)
+	}
+	if c.Name != "Mueller + Soehne" || c.RemittanceText != "Schyot 42, Strasse" {
+		t.Fatalf("unexpected text: %q / %q", c.Name, c.RemittanceText)
+	}
+	want := []Transliteration{
+		{Field: "name", From: "ü", To: "ue"},
+		{Field: "name", From: "&", To: "+"},
+		{Field: "name", From: "ö", To: "oe"},
+		{Field: "remittance_text", From: "С", To: "S"},
+		{Field: "remittance_text", From: "ч", To: "ch"},
+		{Field: "remittance_text", From: "ё", To: "yo"},
+		{Field: "remittance_text", From: "т", To: "t"},
+		{Field: "remittance_text", From: ";", To: ","},
+		{Field: "remittance_text", From: "ß", To: "ss"},
+	}
+	if !reflect.DeepEqual(c.Transliterations, want) {
+		t.Fatalf("unexpected transliterations:\n%v", c.Transliterations)
+	}
+
+	in.Scheme = SchemeBezahlCode
+	if c, err = CleanAndValidate(in); err != nil || c.Name != "Mueller + Soehne" {
+		t.Fatalf("bezahlcode transliterate: %v %+v", err, c)
+	}
+
+	in.SEPACharset = "latin"
+	if _, err := CleanAndValidate(in); err == nil || err.Error() != "unsupported sepa_charset" {
+		t.Fatalf("expected unsupported sepa_charset, got %v", err)
+	}
+
+	if _, err := CleanAndValidate(Input{Scheme: SchemeSwissQR, SEPACharset: "strict"}); err == nil || err.Error() != "sepa_charset is not supported by swiss_qr" {
+		t.Fatalf("expected swiss_qr to reject sepa_charset, got %v", err)
+	}
+}
+
+func TestCleanAndValidate_SEPACharsetDKFIK(t *testing.T) {
+	for _, scheme := range []string{SchemeEPCSCT, SchemeBezahlCode} {
+		for _, mode := range []string{"", SEPACharsetStrict, SEPACharsetTransliterate} {
+			c, err := CleanAndValidate(Input{
+				Scheme:              scheme,
+				SEPACharset:         mode,
+				Name:                "Example ApS",
+				IBAN:                "DE12500105170648489890",
+				BIC:                 "INGDDEFFXXX",
+				Amount:              "10",
+				ReferenceType:       RefTypeDKFIK,
+				RemittanceReference: "+71<000000012345674",
+			})
+			if err != nil {
+				t.Fatalf("%s sepa_charset=%q: %v", scheme, mode, err)
+			}
+			if c.RemittanceReference != "+71<000000012345674" || len(c.Transliterations) != 0 {
+				t.Fatalf("%s sepa_charset=%q: got %q %v", scheme, mode, c.RemittanceReference, c.Transliterations)
+			}
+		}
+	}
+}
+
+func TestSEPAFallbackStaysInSet(t *testing.T) {
+	for _, r := range "ÀÉÎõøÆœŁđЖЩЮΩ«»@#€ " {
+		for _, fr := range sepaFallback(r) {
+			if !sepaAllowed(fr) {
+				t.Fatalf("fallback of %q contains %q", r, fr)
+			}
+		}
+	}
+}
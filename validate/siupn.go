@@ -0,0 +1,206 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var reSIRef = regexp.MustCompile(`^SI([0-9]{2})([0-9-]{0,22})$`)
+
+// ValidSIReference reports whether ref is a Slovenian SIxx reference. Only
+// model 12 carries a check digit (mod 11 over all digits); model 99 must
+// be empty.
+func ValidSIReference(ref string) bool {
+	m := reSIRef.FindStringSubmatch(ref)
+	if m == nil {
+		return false
+	}
+	body := m[2]
+	switch m[1] {
+	case "99":
+		return body == ""
+	case "12":
+		digits := strings.ReplaceAll(body, "-", "")
+		if len(digits) < 2 || len(digits) > 21 || strings.Contains(body, "--") {
+			return false
+		}
+		return siMod11(digits[:len(digits)-1]) == int(digits[len(digits)-1]-'0')
+	default:
+		return body != "" && body[0] != '-' && body[len(body)-1] != '-'
+	}
+}
+
+// siMod11 is the Slovenian mod-11 check digit: weights 2, 3, 4, ... from
+// the right, 11 minus the remainder, and 0 instead of 10 or 11.
+func siMod11(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[len(digits)-1-i]-'0') * (i + 2)
+	}
+	k := 11 - sum%11
+	if k >= 10 {
+		return 0
+	}
+	return k
+}
+
+// latin2Allowed is ISO-8859-2 without control characters, as used by UPN QR
+// and HUB3, whose fields are separated by line feeds.
+func latin2Allowed(r rune) bool {
+	return r >= 0x20 && r != 0x7F && charset.CanEncode(r, charset.ISO8859_2)
+}
+
+func cleanSIUPN(in Input) (*Clean, error) {
+	const scheme = SchemeSIUPN
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"bic", in.BIC},
+		fieldValue{"information", in.Information},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+	if in.OpenAmount {
+		errs.add(fieldErrorf("open_amount", CodeUnsupported, "open_amount is not supported by %s", scheme))
+	}
+	if cur := strings.ToUpper(strings.TrimSpace(in.Currency)); cur != "" && cur != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be EUR"))
+	}
+
+	translits := []Transliteration{}
+	warnings := []Warning{}
+	text := func(field, v string, max int) string {
+		v, translits = transliterateAllowed(field, strings.TrimSpace(v), latin2Allowed, translits)
+		var err error
+		v, warnings, err = truncateField(field, v, max, warnings)
+		errs.add(err)
+		return v
+	}
+
+	// UPN addresses are two free lines: street and number, postal code and town.
+	creditor := Address{
+		Name:   text("name", in.Name, 33),
+		Street: text("creditor_street", joinLine(in.CreditorStreet, in.CreditorBuildingNumber), 33),
+		Town:   text("creditor_town", joinLine(in.CreditorPostalCode, in.CreditorTown), 33),
+	}
+	if creditor.Name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	if creditor.Street == "" {
+		errs.add(fieldErrorf("creditor_street", CodeRequired, "creditor_street is required"))
+	}
+	if creditor.Town == "" {
+		errs.add(fieldErrorf("creditor_town", CodeRequired, "creditor_town is required"))
+	}
+	debtor := Address{
+		Name:   text("debtor_name", in.DebtorName, 33),
+		Street: text("debtor_street", joinLine(in.DebtorStreet, in.DebtorBuildingNumber), 33),
+		Town:   text("debtor_town", joinLine(in.DebtorPostalCode, in.DebtorTown), 33),
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	if iban == "" {
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	} else {
+		errs.add(CheckIBAN(iban))
+	}
+
+	// 11 digits in cents. An open amount is already reported above.
+	var amtCents int64
+	if !in.OpenAmount {
+		var err error
+		amtCents, err = parseSchemeAmount(in, "EUR", 99999999999)
+		errs.add(err)
+	}
+
+	// UPN always carries a reference: SIxx, RF, or SI99 for none.
+	ref := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.RemittanceReference), " ", ""))
+	refOK := true
+	if rfFrom := strings.TrimSpace(in.RFFromInvoice); rfFrom != "" {
+		if ref != "" {
+			refOK = !errs.add(fieldErrorf("rf_from_invoice", CodeConflict, "remittance_reference and rf_from_invoice are mutually exclusive"))
+		} else {
+			var err error
+			ref, err = BuildRF(rfFrom)
+			refOK = !errs.add(err)
+		}
+	}
+	if ref == "" {
+		ref = "SI99"
+	}
+	var refType string
+	switch {
+	case !refOK:
+	case strings.HasPrefix(ref, "RF"):
+		if !ValidRF(ref) {
+			errs.add(fieldErrorf("remittance_reference", CodeInvalidCheckDigit, "invalid creditor reference"))
+		}
+		refType = "RF"
+	case ValidSIReference(ref):
+		refType = ref[:4]
+	default:
+		errs.add(fieldErrorf("remittance_reference", CodeInvalid, "invalid si reference"))
+	}
+
+	purposeText := text("remittance_text", in.RemittanceText, 42)
+	if purposeText == "" {
+		errs.add(fieldErrorf("remittance_text", CodeRequired, "remittance_text is required"))
+	}
+	purpose := strings.TrimSpace(in.Purpose)
+	if purpose == "" {
+		purpose = "OTHR"
+	}
+	purpose, warnings, err := checkPurpose(purpose, warnings)
+	errs.add(err)
+	purpose, warnings, err = truncateField("purpose", purpose, 4, warnings)
+	errs.add(err)
+
+	dueDate, err := cleanDueDate(in.DueDate)
+	errs.add(err)
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "UPNQR",
+		Charset:             charset.ISO8859_2,
+		Name:                creditor.Name,
+		IBAN:                iban,
+		AmountCents:         amtCents,
+		Currency:            "EUR",
+		Purpose:             purpose,
+		RemittanceReference: ref,
+		ReferenceType:       refType,
+		RemittanceText:      purposeText,
+		DueDate:             dueDate,
+		Creditor:            creditor,
+		Debtor:              debtor,
+		Transliterations:    translits,
+		Warnings:            warnings,
+	}, nil
+}
+
+// joinLine joins the non-empty parts of an address line with a space.
+func joinLine(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, " ")
+}
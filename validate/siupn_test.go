@@ -0,0 +1,246 @@
+package validate
+
+import "testing"
+
+func TestValidSIReference(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"SI121234567890120", true},
+		{"SI12123456789012", false},
+		{"SI12", false},
+		{"SI99", true},
+		{"SI99123", false},
+		{"SI0012-345", true},
+		{"SI00-12", false},
+		{"SI1A123", false},
+		{"RF18539007547034", false},
+	}
+	for _, tt := range tests {
+		if got := ValidSIReference(tt.ref); got != tt.want {
+			t.Fatalf("ValidSIReference(%q)=%v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestCleanAndValidate_SIUPN(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+			},
+		},
+		{
+			name: "rf_reference",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "RF18539007547034",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+			},
+		},
+		{
+			name: "no_reference",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+			},
+		},
+		{
+			name: "payer",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+				DebtorName:             "Janez Novak",
+				DebtorTown:             "Maribor",
+				DueDate:                "2026-11-30",
+			},
+		},
+		{
+			name: "reference_bad_check_digit",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI121234567890121",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+			},
+			wantErr: "invalid si reference",
+		},
+		{
+			name: "purpose_unknown",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+				Purpose:                "ZZZZ",
+			},
+			wantErr: "unknown purpose code",
+		},
+		{
+			name: "purpose_text_required",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+			},
+			wantErr: "remittance_text is required",
+		},
+		{
+			name: "creditor_town_required",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+			},
+			wantErr: "creditor_town is required",
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+				OpenAmount:             true,
+			},
+			wantErr: "open_amount is not supported by si_upn",
+		},
+		{
+			name: "bic",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+				BIC:                    "LJBASI2X",
+			},
+			wantErr: "bic is not supported by si_upn",
+		},
+		{
+			name: "currency",
+			in: Input{
+				Scheme:                 "si_upn",
+				Name:                   "Podjetje d.o.o.",
+				IBAN:                   "SI56 1910 0000 0123 438",
+				Amount:                 "125,50",
+				RemittanceReference:    "SI12 1234567890120",
+				RemittanceText:         "Plačilo računa 17",
+				CreditorStreet:         "Slovenska cesta",
+				CreditorBuildingNumber: "1",
+				CreditorPostalCode:     "1000",
+				CreditorTown:           "Ljubljana",
+				Currency:               "CHF",
+			},
+			wantErr: "currency must be EUR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_SIUPNDefaults(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:                 "si_upn",
+		Name:                   "Podjetje d.o.o.",
+		IBAN:                   "SI56 1910 0000 0123 438",
+		Amount:                 "125,50",
+		RemittanceText:         "Račun – 17",
+		CreditorStreet:         "Slovenska cesta",
+		CreditorBuildingNumber: "1",
+		CreditorPostalCode:     "1000",
+		CreditorTown:           "Ljubljana",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.RemittanceReference != "SI99" || cleaned.ReferenceType != "SI99" || cleaned.Purpose != "OTHR" {
+		t.Fatalf("unexpected defaults: %+v", cleaned)
+	}
+	if cleaned.Creditor.Street != "Slovenska cesta 1" || cleaned.Creditor.Town != "1000 Ljubljana" {
+		t.Fatalf("unexpected address lines: %+v", cleaned.Creditor)
+	}
+	// The en dash is outside ISO-8859-2.
+	if cleaned.RemittanceText != "Račun - 17" || len(cleaned.Transliterations) != 1 {
+		t.Fatalf("unexpected transliteration: %q %+v", cleaned.RemittanceText, cleaned.Transliterations)
+	}
+}
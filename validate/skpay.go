@@ -0,0 +1,125 @@
+package validate
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+var reCurrencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func cleanSKPayBySquare(in Input) (*Clean, error) {
+	const scheme = SchemeSKPayBySquare
+
+	var errs errorList
+	warnings := []Warning{}
+
+	// The data model is tab-separated, so values are kept on one line.
+	text := func(field, v string, max int) string {
+		var err error
+		v, warnings, err = truncateField(field, strings.TrimSpace(oneLine.Replace(v)), max, warnings)
+		errs.add(err)
+		return v
+	}
+
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"purpose", in.Purpose},
+		fieldValue{"rf_from_invoice", in.RFFromInvoice},
+		fieldValue{"information", in.Information},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = "EUR"
+	}
+	if !reCurrencyCode.MatchString(currency) {
+		errs.add(fieldErrorf("currency", CodeInvalid, "invalid currency"))
+	}
+
+	name := text("name", in.Name, 70)
+	if name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	if iban == "" {
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	} else {
+		errs.add(CheckIBAN(iban))
+	}
+	bic := strings.ToUpper(strings.TrimSpace(in.BIC))
+	if !errs.failed("iban") {
+		bic, warnings = checkBIC(in, iban, bic, warnings)
+	}
+	if bic != "" && !reBIC.MatchString(bic) {
+		errs.add(fieldErrorf("bic", CodeInvalid, "invalid bic"))
+	}
+
+	var amtCents int64
+	if !errs.failed("currency") {
+		var err error
+		amtCents, err = parseSchemeAmount(in, currency, 99999999999)
+		errs.add(err)
+	}
+
+	dueDate, err := cleanDueDate(in.DueDate)
+	errs.add(err)
+	vs, err := cleanSymbol("variable_symbol", in.VariableSymbol, 10)
+	errs.add(err)
+	ks, err := cleanSymbol("constant_symbol", in.ConstantSymbol, 4)
+	errs.add(err)
+	ss, err := cleanSymbol("specific_symbol", in.SpecificSymbol, 10)
+	errs.add(err)
+
+	ref := text("remittance_reference", in.RemittanceReference, 35)
+	message := text("remittance_text", in.RemittanceText, 140)
+
+	// The beneficiary address is two free lines of 70 characters; street and
+	// building number go on the first, postal code and town on the second.
+	creditor := Address{
+		Name:           name,
+		Street:         text("creditor_street", in.CreditorStreet, 70),
+		BuildingNumber: text("creditor_building_number", in.CreditorBuildingNumber, 16),
+		PostalCode:     text("creditor_postal_code", in.CreditorPostalCode, 16),
+		Town:           text("creditor_town", in.CreditorTown, 35),
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "1.1.0",
+		Charset:             charset.UTF8,
+		Name:                name,
+		IBAN:                iban,
+		BIC:                 bic,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            currency,
+		RemittanceReference: ref,
+		RemittanceText:      message,
+		DueDate:             dueDate,
+		VariableSymbol:      vs,
+		ConstantSymbol:      ks,
+		SpecificSymbol:      ss,
+		Creditor:            creditor,
+		Transliterations:    []Transliteration{},
+		Warnings:            warnings,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package validate
+
+import "testing"
+
+func TestCleanAndValidate_SKPayBySquare(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "valid",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+			},
+		},
+		{
+			name: "czk_with_bic",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+				Currency:       "czk",
+				BIC:            "TATRSKBX",
+			},
+		},
+		{
+			name: "address",
+			in: Input{
+				Scheme:             "sk_pay_by_square",
+				Name:               "Príklad s.r.o.",
+				IBAN:               "SK31 1200 0000 1987 4263 7541",
+				Amount:             "125.50",
+				VariableSymbol:     "2026001234",
+				DueDate:            "2026-11-30",
+				CreditorStreet:     "Hlavná",
+				CreditorPostalCode: "811 01",
+				CreditorTown:       "Bratislava",
+			},
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+				OpenAmount:     true,
+			},
+		},
+		{
+			name: "reference_and_note",
+			in: Input{
+				Scheme:              "sk_pay_by_square",
+				Name:                "Príklad s.r.o.",
+				IBAN:                "SK31 1200 0000 1987 4263 7541",
+				Amount:              "125.50",
+				VariableSymbol:      "2026001234",
+				DueDate:             "2026-11-30",
+				RemittanceReference: "RF18539007547034",
+				RemittanceText:      "Faktúra 17",
+			},
+		},
+		{
+			name: "currency_invalid",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+				Currency:       "EURO",
+			},
+			wantErr: "invalid currency",
+		},
+		{
+			name: "constant_symbol_too_long",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+				ConstantSymbol: "03080",
+			},
+			wantErr: "invalid constant_symbol",
+		},
+		{
+			name: "specific_symbol_letters",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+				SpecificSymbol: "S42",
+			},
+			wantErr: "invalid specific_symbol",
+		},
+		{
+			name: "due_date_invalid",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           "Príklad s.r.o.",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-02-30",
+			},
+			wantErr: "invalid due_date",
+		},
+		{
+			name: "creditor_country_not_supported",
+			in: Input{
+				Scheme:          "sk_pay_by_square",
+				Name:            "Príklad s.r.o.",
+				IBAN:            "SK31 1200 0000 1987 4263 7541",
+				Amount:          "125.50",
+				VariableSymbol:  "2026001234",
+				DueDate:         "2026-11-30",
+				CreditorCountry: "SK",
+			},
+			wantErr: "creditor_country is not supported by sk_pay_by_square",
+		},
+		{
+			name: "name_required",
+			in: Input{
+				Scheme:         "sk_pay_by_square",
+				Name:           " ",
+				IBAN:           "SK31 1200 0000 1987 4263 7541",
+				Amount:         "125.50",
+				VariableSymbol: "2026001234",
+				DueDate:        "2026-11-30",
+			},
+			wantErr: "name is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_SKPayBySquareSingleLine(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:         "sk_pay_by_square",
+		Name:           "Príklad s.r.o.",
+		IBAN:           "SK31 1200 0000 1987 4263 7541",
+		Amount:         "125.50",
+		VariableSymbol: "2026001234",
+		DueDate:        "2026-11-30",
+		RemittanceText: "line one\tline\r\ntwo",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.RemittanceText != "line one line two" || cleaned.Currency != "EUR" || cleaned.AmountCents != 12550 {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+}
@@ -0,0 +1,220 @@
+package validate
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/safe-cap/sepaqx/charset"
+)
+
+// Swiss QR-bill reference types.
+const (
+	SwissRefQRR  = "QRR"
+	SwissRefSCOR = "SCOR"
+	SwissRefNON  = "NON"
+)
+
+var (
+	reCountry = regexp.MustCompile(`^[A-Z]{2}$`)
+	reQRRef   = regexp.MustCompile(`^[0-9]{27}$`)
+)
+
+// qrrCarry is the table of the recursive mod-10 check used by QR references.
+var qrrCarry = [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+
+// ValidQRReference reports whether ref is a 27-digit Swiss QR reference with
+// a correct recursive mod-10 check digit.
+func ValidQRReference(ref string) bool {
+	if !reQRRef.MatchString(ref) {
+		return false
+	}
+	carry := 0
+	for _, r := range ref[:26] {
+		carry = qrrCarry[(carry+int(r-'0'))%10]
+	}
+	return (10-carry)%10 == int(ref[26]-'0')
+}
+
+// IsQRIBAN reports whether iban is a Swiss or Liechtenstein QR-IBAN, whose
+// institution ID lies in the reserved range 30000-31999.
+func IsQRIBAN(iban string) bool {
+	if len(iban) != 21 || (iban[:2] != "CH" && iban[:2] != "LI") {
+		return false
+	}
+	iid, err := strconv.Atoi(iban[4:9])
+	return err == nil && iid >= 30000 && iid <= 31999
+}
+
+// swissAllowed is the Swiss Payment Standards character set: Basic Latin,
+// Latin-1 Supplement, Latin Extended-A, Ș ș Ț ț and the euro sign.
+func swissAllowed(r rune) bool {
+	switch {
+	case r >= 0x20 && r <= 0x7E, r >= 0xA0 && r <= 0x17F, r >= 0x218 && r <= 0x21B, r == '€':
+		return true
+	}
+	return false
+}
+
+func cleanSwissQR(in Input) (*Clean, error) {
+	const scheme = SchemeSwissQR
+
+	var errs errorList
+	errs.add(rejectUnsupported(scheme,
+		fieldValue{"version", in.Version},
+		fieldValue{"charset", in.Charset},
+		fieldValue{"bic", in.BIC},
+		fieldValue{"purpose", in.Purpose},
+		fieldValue{"due_date", in.DueDate},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+		fieldValue{"reference_type", in.ReferenceType},
+		fieldValue{"sepa_charset", in.SEPACharset},
+	))
+
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	if currency == "" {
+		currency = "CHF"
+	}
+	if currency != "CHF" && currency != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be CHF or EUR"))
+	}
+
+	translits := []Transliteration{}
+	warnings := []Warning{}
+	text := func(field, v string, max int) string {
+		v, translits = transliterateAllowed(field, strings.TrimSpace(v), swissAllowed, translits)
+		var err error
+		v, warnings, err = truncateField(field, v, max, warnings)
+		errs.add(err)
+		return v
+	}
+
+	creditor := Address{
+		Name:           text("name", in.Name, 70),
+		Street:         text("creditor_street", in.CreditorStreet, 70),
+		BuildingNumber: text("creditor_building_number", in.CreditorBuildingNumber, 16),
+		PostalCode:     text("creditor_postal_code", in.CreditorPostalCode, 16),
+		Town:           text("creditor_town", in.CreditorTown, 35),
+		Country:        strings.ToUpper(strings.TrimSpace(in.CreditorCountry)),
+	}
+	if creditor.Name == "" {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
+	}
+	errs.add(checkSwissAddress("creditor", creditor))
+
+	debtor := Address{
+		Name:           text("debtor_name", in.DebtorName, 70),
+		Street:         text("debtor_street", in.DebtorStreet, 70),
+		BuildingNumber: text("debtor_building_number", in.DebtorBuildingNumber, 16),
+		PostalCode:     text("debtor_postal_code", in.DebtorPostalCode, 16),
+		Town:           text("debtor_town", in.DebtorTown, 35),
+		Country:        strings.ToUpper(strings.TrimSpace(in.DebtorCountry)),
+	}
+	if !debtor.IsZero() {
+		if debtor.Name == "" {
+			errs.add(fieldErrorf("debtor_name", CodeRequired, "debtor_name is required"))
+		}
+		errs.add(checkSwissAddress("debtor", debtor))
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case ibanCountry(iban) != "CH" && ibanCountry(iban) != "LI":
+		errs.add(fieldErrorf("iban", CodeUnsupported, "iban must be a CH or LI IBAN"))
+	}
+
+	var amtCents int64
+	if !errs.failed("currency") {
+		var err error
+		amtCents, err = parseSchemeAmount(in, currency, 99999999999)
+		errs.add(err)
+	}
+
+	// A QR-IBAN always carries a QR reference; a regular IBAN carries an
+	// RF creditor reference or none at all. Which one applies depends on
+	// the IBAN, so a bad IBAN skips the reference check.
+	ref := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.RemittanceReference), " ", ""))
+	refOK := true
+	if rfFrom := strings.TrimSpace(in.RFFromInvoice); rfFrom != "" {
+		if ref != "" {
+			refOK = !errs.add(fieldErrorf("rf_from_invoice", CodeConflict, "remittance_reference and rf_from_invoice are mutually exclusive"))
+		} else {
+			var err error
+			ref, err = BuildRF(rfFrom)
+			refOK = !errs.add(err)
+		}
+	}
+	var refType string
+	switch {
+	case !refOK || errs.failed("iban"):
+	case IsQRIBAN(iban):
+		if ref == "" {
+			errs.add(fieldErrorf("remittance_reference", CodeRequired, "qr reference is required for a QR-IBAN"))
+		} else if !ValidQRReference(ref) {
+			errs.add(fieldErrorf("remittance_reference", CodeInvalid, "invalid qr reference"))
+		}
+		refType = SwissRefQRR
+	case ref == "":
+		refType = SwissRefNON
+	case ValidRF(ref):
+		refType = SwissRefSCOR
+	default:
+		errs.add(fieldErrorf("remittance_reference", CodeConflict, "reference requires a QR-IBAN or an RF creditor reference"))
+	}
+
+	message, translits := transliterateAllowed("remittance_text", strings.TrimSpace(in.RemittanceText), swissAllowed, translits)
+	billInfo, translits := transliterateAllowed("information", strings.TrimSpace(in.Information), swissAllowed, translits)
+	if utf8.RuneCountInString(message)+utf8.RuneCountInString(billInfo) > 140 {
+		errs.add(fieldErrorf("remittance_text", CodeTooLong, "remittance_text and information exceed 140 characters together"))
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	return &Clean{
+		Scheme:              scheme,
+		Version:             "0200",
+		Charset:             charset.UTF8,
+		Name:                creditor.Name,
+		IBAN:                iban,
+		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            currency,
+		RemittanceReference: ref,
+		ReferenceType:       refType,
+		RemittanceText:      message,
+		Information:         billInfo,
+		Creditor:            creditor,
+		Debtor:              debtor,
+		Transliterations:    translits,
+		Warnings:            warnings,
+	}, nil
+}
+
+// checkSwissAddress validates a structured (type S) address. Postal code,
+// town and country are mandatory; street and building number are optional.
+func checkSwissAddress(party string, a Address) error {
+	var errs errorList
+	if a.PostalCode == "" {
+		errs.add(fieldErrorf(party+"_postal_code", CodeRequired, "%s_postal_code is required", party))
+	}
+	if a.Town == "" {
+		errs.add(fieldErrorf(party+"_town", CodeRequired, "%s_town is required", party))
+	}
+	if a.Country == "" {
+		errs.add(fieldErrorf(party+"_country", CodeRequired, "%s_country is required", party))
+	} else if !reCountry.MatchString(a.Country) {
+		errs.add(fieldErrorf(party+"_country", CodeInvalid, "invalid %s_country", party))
+	}
+	return errs.err()
+}
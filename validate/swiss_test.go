@@ -0,0 +1,376 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidQRReference(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"210000000003139471430009017", true},
+		{"000000000000000000000000000", true},
+		{"210000000003139471430009018", false},
+		{"21000000000313947143000901", false},
+		{"21000000000313947143000901A", false},
+	}
+	for _, tt := range tests {
+		if got := ValidQRReference(tt.in); got != tt.want {
+			t.Fatalf("ValidQRReference(%q)=%v want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsQRIBAN(t *testing.T) {
+	if !IsQRIBAN("CH4431999123000889012") {
+		t.Fatalf("expected QR-IBAN")
+	}
+	if IsQRIBAN("CH9300762011623852957") {
+		t.Fatalf("regular IBAN reported as QR-IBAN")
+	}
+	if IsQRIBAN("DE12500105170648489890") {
+		t.Fatalf("German IBAN reported as QR-IBAN")
+	}
+}
+
+func TestCleanAndValidate_SwissQR(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Input
+		wantErr string
+	}{
+		{
+			name: "qr_iban_with_qrr",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+		},
+		{
+			name: "open_amount",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				OpenAmount:             true,
+			},
+		},
+		{
+			name: "eur",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				Currency:               "EUR",
+			},
+		},
+		{
+			name: "amount_with_chf_marker",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "CHF 1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+		},
+		{
+			name: "amount_with_eur_marker",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "EUR 1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "unsupported currency: EUR (only CHF is allowed)",
+		},
+		{
+			name: "currency_unsupported",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				Currency:               "USD",
+			},
+			wantErr: "currency must be CHF or EUR",
+		},
+		{
+			name: "qr_iban_without_reference",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "qr reference is required for a QR-IBAN",
+		},
+		{
+			name: "qr_reference_bad_check",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "210000000003139471430009018",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "invalid qr reference",
+		},
+		{
+			name: "regular_iban_scor",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH9300762011623852957",
+				Amount:                 "1949.75",
+				RemittanceReference:    "RF18539007547034",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+		},
+		{
+			name: "regular_iban_non",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH9300762011623852957",
+				Amount:                 "1949.75",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+		},
+		{
+			name: "regular_iban_qrr",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH9300762011623852957",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "reference requires a QR-IBAN or an RF creditor reference",
+		},
+		{
+			name: "non_swiss_iban",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "DE12500105170648489890",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "iban must be a CH or LI IBAN",
+		},
+		{
+			name: "bic_not_supported",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				BIC:                    "UBSWCHZH80A",
+			},
+			wantErr: "bic is not supported by swiss_qr",
+		},
+		{
+			name: "creditor_town_missing",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorCountry:        "ch",
+			},
+			wantErr: "creditor_town is required",
+		},
+		{
+			name: "creditor_country_invalid",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "CHE",
+			},
+			wantErr: "invalid creditor_country",
+		},
+		{
+			name: "debtor_without_name",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				DebtorTown:             "Rorschach",
+			},
+			wantErr: "debtor_name is required",
+		},
+		{
+			name: "debtor_complete",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				DebtorName:             "Pia-Maria Rutschmann-Schnyder",
+				DebtorStreet:           "Grosse Marktgasse",
+				DebtorBuildingNumber:   "28",
+				DebtorPostalCode:       "9400",
+				DebtorTown:             "Rorschach",
+				DebtorCountry:          "CH",
+			},
+		},
+		{
+			name: "text_too_long",
+			in: Input{
+				Scheme:                 "swiss_qr",
+				Name:                   "Robert Schneider AG",
+				IBAN:                   "CH44 3199 9123 0008 8901 2",
+				Amount:                 "1949.75",
+				RemittanceReference:    "21 00000 00003 13947 14300 09017",
+				CreditorStreet:         "Rue du Lac",
+				CreditorBuildingNumber: "1268",
+				CreditorPostalCode:     "2501",
+				CreditorTown:           "Biel",
+				CreditorCountry:        "ch",
+				RemittanceText:         strings.Repeat("a", 100),
+				Information:            strings.Repeat("b", 41),
+			},
+			wantErr: "remittance_text and information exceed 140 characters together",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CleanAndValidate(tt.in)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCleanAndValidate_SwissQRNormalizes(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:                 "swiss_qr",
+		Name:                   "Сервис AG",
+		IBAN:                   "CH44 3199 9123 0008 8901 2",
+		Amount:                 "1949.75",
+		RemittanceReference:    "21 00000 00003 13947 14300 09017",
+		CreditorStreet:         "Rue du Lac",
+		CreditorBuildingNumber: "1268",
+		CreditorPostalCode:     "2501",
+		CreditorTown:           "Biel",
+		CreditorCountry:        "ch",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.IBAN != "CH4431999123000889012" || cleaned.RemittanceReference != "210000000003139471430009017" {
+		t.Fatalf("unexpected normalization: %q %q", cleaned.IBAN, cleaned.RemittanceReference)
+	}
+	if cleaned.ReferenceType != SwissRefQRR || cleaned.Currency != "CHF" || cleaned.Creditor.Country != "CH" {
+		t.Fatalf("unexpected clean: %+v", cleaned)
+	}
+	if cleaned.Name != "Servis AG" || cleaned.Creditor.Name != cleaned.Name || len(cleaned.Transliterations) == 0 {
+		t.Fatalf("expected transliterated name, got %q %+v", cleaned.Name, cleaned.Transliterations)
+	}
+	if !cleaned.Debtor.IsZero() {
+		t.Fatalf("expected empty debtor, got %+v", cleaned.Debtor)
+	}
+}
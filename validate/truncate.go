@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+var strictTruncation atomic.Bool
+
+// SetStrictTruncation rejects values longer than their field instead of
+// cutting them with a warning.
+func SetStrictTruncation(enabled bool) {
+	strictTruncation.Store(enabled)
+}
+
+// truncateField cuts v to max runes like truncateRunes and reports the cut
+// as a "truncated" warning, or as an error in strict truncation mode.
+func truncateField(field, v string, max int, acc []Warning) (string, []Warning, error) {
+	n := utf8.RuneCountInString(v)
+	if n <= max {
+		return v, acc, nil
+	}
+	if strictTruncation.Load() {
+		return truncateRunes(v, max), acc, fieldErrorf(field, CodeTooLong, "%s exceeds %d characters", field, max)
+	}
+	return truncateRunes(v, max), append(acc, Warning{
+		Field:          field,
+		Code:           "truncated",
+		Message:        fmt.Sprintf("%s truncated from %d to %d characters", field, n, max),
+		OriginalLength: n,
+		KeptLength:     max,
+	}), nil
+}
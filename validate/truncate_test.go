@@ -0,0 +1,60 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanAndValidate_TruncationWarnings(t *testing.T) {
+	in := Input{
+		Name:           strings.Repeat("N", 75),
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "1",
+		RemittanceText: strings.Repeat("x", 150),
+	}
+	cleaned, err := CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Warning{
+		{Field: "name", Code: "truncated", Message: "name truncated from 75 to 70 characters", OriginalLength: 75, KeptLength: 70},
+		{Field: "remittance_text", Code: "truncated", Message: "remittance_text truncated from 150 to 140 characters", OriginalLength: 150, KeptLength: 140},
+	}
+	if len(cleaned.Warnings) != len(want) {
+		t.Fatalf("unexpected warnings: %+v", cleaned.Warnings)
+	}
+	for i := range want {
+		if cleaned.Warnings[i] != want[i] {
+			t.Fatalf("warning %d = %+v, want %+v", i, cleaned.Warnings[i], want[i])
+		}
+	}
+	if len(cleaned.Name) != 70 {
+		t.Fatalf("name not truncated: %q", cleaned.Name)
+	}
+
+	SetStrictTruncation(true)
+	defer SetStrictTruncation(false)
+	_, err = CleanAndValidate(in)
+	fieldErrs := FieldErrors(err)
+	if len(fieldErrs) != 2 || fieldErrs[0].Field != "name" || fieldErrs[0].Code != CodeTooLong ||
+		fieldErrs[0].Message != "name exceeds 70 characters" || fieldErrs[1].Field != "remittance_text" {
+		t.Fatalf("unexpected strict errors: %+v", fieldErrs)
+	}
+}
+
+func TestCleanAndValidate_TruncationWarningsOtherSchemes(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Scheme:         SchemeCZSPD,
+		Name:           strings.Repeat("N", 40),
+		IBAN:           "CZ6508000000192000145399",
+		Amount:         "1",
+		RemittanceText: "short",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cleaned.Warnings) != 1 || cleaned.Warnings[0].Field != "name" || cleaned.Warnings[0].KeptLength != 35 {
+		t.Fatalf("unexpected warnings: %+v", cleaned.Warnings)
+	}
+}
@@ -5,39 +5,207 @@ import (
 	"regexp"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/safe-cap/sepaqx/charset"
 )
 
 var (
 	reBIC = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
 )
 
+// Supported values of Input.Scheme.
+const (
+	SchemeEPCSCT        = "epc_sct"
+	SchemeSwissQR       = "swiss_qr"
+	SchemeCZSPD         = "cz_spd"
+	SchemeSKPayBySquare = "sk_pay_by_square"
+	SchemeSIUPN         = "si_upn"
+	SchemeHUMNB         = "hu_mnb"
+	SchemeHRHUB3        = "hr_hub3"
+	SchemeFIBarcode     = "fi_barcode"
+	SchemeBezahlCode    = "bezahlcode"
+)
+
 type Input struct {
 	Scheme              string `json:"scheme"`
+	Version             string `json:"version"`
+	Charset             string `json:"charset"`
+	SEPACharset         string `json:"sepa_charset"`
 	Name                string `json:"name"`
 	IBAN                string `json:"iban"`
 	BIC                 string `json:"bic"`
+	BICFromIBAN         bool   `json:"bic_from_iban"`
+	IBANFormat          string `json:"iban_format"`
 	Amount              string `json:"amount"`
 	AmountFormat        string `json:"amount_format"`
+	OpenAmount          bool   `json:"open_amount"`
+	Currency            string `json:"currency"`
 	Purpose             string `json:"purpose"`
 	RemittanceReference string `json:"remittance_reference"`
+	ReferenceType       string `json:"reference_type"`
+	RFFromInvoice       string `json:"rf_from_invoice"`
 	RemittanceText      string `json:"remittance_text"`
 	Information         string `json:"information"`
+	DueDate             string `json:"due_date"`
+
+	VariableSymbol string `json:"variable_symbol"`
+	ConstantSymbol string `json:"constant_symbol"`
+	SpecificSymbol string `json:"specific_symbol"`
+
+	TransferType string `json:"transfer_type"`
+	ValidUntil   string `json:"valid_until"`
+
+	ExecutionDate string `json:"execution_date"`
+	Period        string `json:"period"`
+
+	CreditorStreet         string `json:"creditor_street"`
+	CreditorBuildingNumber string `json:"creditor_building_number"`
+	CreditorPostalCode     string `json:"creditor_postal_code"`
+	CreditorTown           string `json:"creditor_town"`
+	CreditorCountry        string `json:"creditor_country"`
+
+	DebtorName           string `json:"debtor_name"`
+	DebtorStreet         string `json:"debtor_street"`
+	DebtorBuildingNumber string `json:"debtor_building_number"`
+	DebtorPostalCode     string `json:"debtor_postal_code"`
+	DebtorTown           string `json:"debtor_town"`
+	DebtorCountry        string `json:"debtor_country"`
+
+	// AllowNonSEPA skips the SEPA reachability check (per-key override,
+	// never read from requests).
+	AllowNonSEPA bool `json:"-"`
 }
 
 type Clean struct {
 	Scheme              string
+	Version             string
+	Charset             int
 	Name                string
 	IBAN                string
 	BIC                 string
 	AmountCents         int64
+	OpenAmount          bool
+	Currency            string
 	Purpose             string
 	RemittanceReference string
+	ReferenceType       string
 	RemittanceText      string
 	Information         string
+	DueDate             string
+	VariableSymbol      string
+	ConstantSymbol      string
+	SpecificSymbol      string
+	TransferType        string
+	ValidUntil          string
+	ExecutionDate       string
+	Period              string
+	// BezahlCodeKind is the BezahlCode URI authority, such as
+	// BezahlCodeSinglePayment; empty for other schemes.
+	BezahlCodeKind   string
+	Creditor         Address
+	Debtor           Address
+	Transliterations []Transliteration
+	Warnings         []Warning
+	ByteUsage        ByteUsage
+}
+
+// Warning describes an input that was accepted but is likely to cause
+// trouble downstream.
+type Warning struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// OriginalLength and KeptLength are set for "truncated" warnings.
+	OriginalLength int `json:"original_length,omitempty"`
+	KeptLength     int `json:"kept_length,omitempty"`
+}
+
+// Transliteration records a character that was replaced because the
+// selected charset cannot represent it.
+type Transliteration struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Address is a structured postal address. Schemes without address lines
+// leave it empty; Name mirrors Clean.Name for the creditor.
+type Address struct {
+	Name           string `json:"name,omitempty"`
+	Street         string `json:"street,omitempty"`
+	BuildingNumber string `json:"building_number,omitempty"`
+	PostalCode     string `json:"postal_code,omitempty"`
+	Town           string `json:"town,omitempty"`
+	Country        string `json:"country,omitempty"`
+}
+
+// IsZero reports whether no address field is set.
+func (a Address) IsZero() bool {
+	return a == Address{}
 }
 
+// CleanAndValidate normalizes in for its scheme. On failure the error is a
+// *ValidationError listing every failed check; its Error method returns
+// the first one.
 func CleanAndValidate(in Input) (*Clean, error) {
 	scheme := strings.ToLower(strings.TrimSpace(in.Scheme))
+	if scheme == "" {
+		scheme = SchemeEPCSCT
+	}
+	lenientOCR, err := ibanFormatLenient(in.IBANFormat)
+	if err != nil {
+		var errs errorList
+		errs.add(err)
+		return nil, errs.err()
+	}
+	var ocrWarnings []Warning
+	if lenientOCR {
+		in, ocrWarnings = correctAccountOCR(in)
+	}
+	var c *Clean
+	switch scheme {
+	case SchemeEPCSCT:
+		c, err = cleanEPC(in)
+	case SchemeSwissQR:
+		c, err = cleanSwissQR(in)
+	case SchemeCZSPD:
+		c, err = cleanCZSPD(in)
+	case SchemeSKPayBySquare:
+		c, err = cleanSKPayBySquare(in)
+	case SchemeSIUPN:
+		c, err = cleanSIUPN(in)
+	case SchemeHUMNB:
+		c, err = cleanHUMNB(in)
+	case SchemeHRHUB3:
+		c, err = cleanHRHUB3(in)
+	case SchemeFIBarcode:
+		c, err = cleanFIBarcode(in)
+	case SchemeBezahlCode:
+		c, err = cleanBezahlCode(in)
+	default:
+		err = fieldErrorf("scheme", CodeUnsupported, "unsupported scheme")
+	}
+	var errs errorList
+	if errs.add(err) {
+		// A value left as sent because its OCR readings were ambiguous
+		// usually fails; say why next to its error.
+		for _, w := range ocrWarnings {
+			if w.Code == CodeOCRAmbiguous && errs.failed(w.Field) {
+				errs.errs = append(errs.errs, fieldErrorf(w.Field, CodeOCRAmbiguous, "%s", w.Message))
+			}
+		}
+		return nil, errs.err()
+	}
+	if len(ocrWarnings) > 0 {
+		c.Warnings = append(ocrWarnings, c.Warnings...)
+	}
+	c.Warnings = accountCheckWarnings(c.IBAN, c.Warnings)
+	return c, nil
+}
+
+func cleanEPC(in Input) (*Clean, error) {
+	version := strings.TrimSpace(in.Version)
 	name := strings.TrimSpace(in.Name)
 	purpose := strings.TrimSpace(in.Purpose)
 	remRef := strings.TrimSpace(in.RemittanceReference)
@@ -47,66 +215,280 @@ func CleanAndValidate(in Input) (*Clean, error) {
 	iban := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(in.IBAN), " ", ""))
 	bic := strings.ToUpper(strings.TrimSpace(in.BIC))
 
-	if scheme == "" {
-		scheme = "epc_sct"
-	}
-	if scheme != "epc_sct" {
-		return nil, fmt.Errorf("unsupported scheme")
+	var errs errorList
+	if cur := strings.ToUpper(strings.TrimSpace(in.Currency)); cur != "" && cur != "EUR" {
+		errs.add(fieldErrorf("currency", CodeUnsupported, "currency must be EUR"))
 	}
+	errs.add(rejectUnsupported(SchemeEPCSCT,
+		fieldValue{"due_date", in.DueDate},
+		fieldValue{"variable_symbol", in.VariableSymbol},
+		fieldValue{"constant_symbol", in.ConstantSymbol},
+		fieldValue{"specific_symbol", in.SpecificSymbol},
+		fieldValue{"creditor_street", in.CreditorStreet},
+		fieldValue{"creditor_building_number", in.CreditorBuildingNumber},
+		fieldValue{"creditor_postal_code", in.CreditorPostalCode},
+		fieldValue{"creditor_town", in.CreditorTown},
+		fieldValue{"creditor_country", in.CreditorCountry},
+		fieldValue{"debtor_name", in.DebtorName},
+		fieldValue{"debtor_street", in.DebtorStreet},
+		fieldValue{"debtor_building_number", in.DebtorBuildingNumber},
+		fieldValue{"debtor_postal_code", in.DebtorPostalCode},
+		fieldValue{"debtor_town", in.DebtorTown},
+		fieldValue{"debtor_country", in.DebtorCountry},
+		fieldValue{"transfer_type", in.TransferType},
+		fieldValue{"valid_until", in.ValidUntil},
+		fieldValue{"execution_date", in.ExecutionDate},
+		fieldValue{"period", in.Period},
+	))
 
-	if name == "" {
-		return nil, fmt.Errorf("name is required")
+	versionOK := true
+	switch version {
+	case "", "1", "001":
+		version = "001"
+	case "2", "002":
+		version = "002"
+	default:
+		versionOK = !errs.add(fieldErrorf("version", CodeUnsupported, "unsupported version"))
 	}
-	if iban == "" {
-		return nil, fmt.Errorf("iban is required")
+
+	cs, ok := charset.Parse(in.Charset)
+	if !ok {
+		errs.add(fieldErrorf("charset", CodeUnsupported, "unsupported charset"))
+		cs = charset.UTF8
 	}
-	if !ValidIBAN(iban) {
-		return nil, fmt.Errorf("invalid iban")
+	sepaMode, err := parseSEPACharset(in.SEPACharset)
+	errs.add(err)
+	translits := []Transliteration{}
+	for _, f := range []struct {
+		name string
+		v    *string
+	}{
+		{"name", &name},
+		{"remittance_reference", &remRef},
+		{"remittance_text", &remText},
+		{"information", &info},
+	} {
+		// Typed references are checked by their own rules and keep their
+		// canonical form, such as the "<" of dk_fik.
+		if f.name == "remittance_reference" && strings.TrimSpace(in.ReferenceType) != "" {
+			continue
+		}
+		*f.v, translits, err = cleanSEPAText(sepaMode, f.name, *f.v, translits)
+		errs.add(err)
 	}
+	name, translits = transliterateField("name", name, cs, translits)
+	remRef, translits = transliterateField("remittance_reference", remRef, cs, translits)
+	remText, translits = transliterateField("remittance_text", remText, cs, translits)
+	info, translits = transliterateField("information", info, cs, translits)
 
-	// BIC is optional in EPC spec, but you asked to verify it.
-	if bic == "" {
-		return nil, fmt.Errorf("bic is required")
+	if name == "" && !errs.failed("name") {
+		errs.add(fieldErrorf("name", CodeRequired, "name is required"))
 	}
-	if !reBIC.MatchString(bic) {
-		return nil, fmt.Errorf("invalid bic")
+	switch {
+	case iban == "":
+		errs.add(fieldErrorf("iban", CodeRequired, "iban is required"))
+	case errs.add(CheckIBAN(iban)):
+	case errs.add(checkSEPA(in, iban)):
 	}
-
-	amtCents, err := parseAmountEUR(in.Amount, in.AmountFormat)
-	if err != nil {
-		return nil, err
+	ibanOK := !errs.failed("iban")
+	bicWarnings := []Warning{}
+	if ibanOK {
+		bic, bicWarnings = checkBIC(in, iban, bic, bicWarnings)
 	}
-	if amtCents <= 0 {
-		return nil, fmt.Errorf("amount must be > 0")
+
+	// Version 001 always carries a BIC. Version 002 may omit it, but only
+	// for payees inside the EEA.
+	if bic == "" {
+		if version == "001" && versionOK {
+			errs.add(fieldErrorf("bic", CodeRequired, "bic is required"))
+		} else if version == "002" && ibanOK && !inEEA(ibanCountry(iban)) {
+			errs.add(fieldErrorf("bic", CodeRequired, "bic is required outside the EEA"))
+		}
+	} else if !reBIC.MatchString(bic) {
+		errs.add(fieldErrorf("bic", CodeInvalid, "invalid bic"))
 	}
-	if amtCents > 99999999999 {
-		return nil, fmt.Errorf("amount too large")
+
+	// Open-amount codes leave the amount line empty for the payer to fill
+	// in. It has to be asked for explicitly; a zero amount is still an error.
+	var amtCents int64
+	if in.OpenAmount {
+		if strings.TrimSpace(in.Amount) != "" {
+			errs.add(fieldErrorf("open_amount", CodeConflict, "amount and open_amount are mutually exclusive"))
+		}
+	} else {
+		amtCents, err = parseAmountEUR(in.Amount, in.AmountFormat)
+		switch {
+		case errs.add(err):
+		case amtCents <= 0:
+			errs.add(fieldErrorf("amount", CodeInvalid, "amount must be > 0"))
+		case amtCents > 99999999999:
+			errs.add(fieldErrorf("amount", CodeTooLarge, "amount too large"))
+		}
 	}
 
-	name = truncateRunes(name, 70)
+	// Structured references: build an RF reference from a raw invoice
+	// number on request, normalize typed national references, and check
+	// anything that claims to be RF.
+	remRef, refType, err := cleanReference(in, remRef)
+	errs.add(err)
+
 	if purpose == "" {
 		purpose = "GDDS"
 	}
-	purpose = strings.ToUpper(truncateRunes(purpose, 4))
-	remRef = truncateRunes(remRef, 25)
-	remText = truncateRunes(remText, 140)
-	info = truncateRunes(info, 70)
+	purpose, warnings, err := checkPurpose(purpose, bicWarnings)
+	errs.add(err)
+
+	for _, f := range []struct {
+		name string
+		v    *string
+		max  int
+	}{
+		{"name", &name, 70},
+		{"purpose", &purpose, 4},
+		{"remittance_reference", &remRef, 25},
+		{"remittance_text", &remText, 140},
+		{"information", &info, 70},
+	} {
+		*f.v, warnings, err = truncateField(f.name, *f.v, f.max, warnings)
+		errs.add(err)
+	}
 
 	if remRef != "" && remText != "" {
-		return nil, fmt.Errorf("remittance_reference and remittance_text are mutually exclusive")
+		errs.add(fieldErrorf("remittance_reference", CodeConflict, "remittance_reference and remittance_text are mutually exclusive"))
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
 	}
 
-	return &Clean{
-		Scheme:              scheme,
+	c := &Clean{
+		Scheme:              SchemeEPCSCT,
+		Version:             version,
+		Charset:             cs,
 		Name:                name,
 		IBAN:                iban,
 		BIC:                 bic,
 		AmountCents:         amtCents,
+		OpenAmount:          in.OpenAmount,
+		Currency:            "EUR",
 		Purpose:             purpose,
 		RemittanceReference: remRef,
+		ReferenceType:       refType,
 		RemittanceText:      remText,
 		Information:         info,
-	}, nil
+		Transliterations:    translits,
+		Warnings:            warnings,
+	}
+	c.ByteUsage = epcByteUsage(c)
+	if c.ByteUsage.Total > EPCMaxPayloadBytes {
+		return nil, &PayloadTooLargeError{Usage: c.ByteUsage}
+	}
+	return c, nil
+}
+
+func transliterateField(field, v string, cs int, acc []Transliteration) (string, []Transliteration) {
+	out, reps := charset.Transliterate(v, cs)
+	for _, r := range reps {
+		acc = append(acc, Transliteration{Field: field, From: r.From, To: r.To})
+	}
+	return out, acc
+}
+
+// fieldValue pairs an input field name with its raw value.
+type fieldValue struct {
+	name  string
+	value string
+}
+
+// rejectUnsupported fails on every set field the scheme has no slot for.
+func rejectUnsupported(scheme string, fields ...fieldValue) error {
+	var errs errorList
+	for _, f := range fields {
+		if strings.TrimSpace(f.value) != "" {
+			errs.add(fieldErrorf(f.name, CodeUnsupported, "%s is not supported by %s", f.name, scheme))
+		}
+	}
+	return errs.err()
+}
+
+// checkPurpose uppercases a purpose code and checks it against the
+// ExternalPurpose1Code list. Banks drop purpose codes they do not know, so
+// only catalogue codes are accepted unless lenient mode is on, which turns
+// the error into a warning.
+func checkPurpose(purpose string, warnings []Warning) (string, []Warning, error) {
+	purpose = strings.ToUpper(purpose)
+	if KnownPurposeCode(purpose) {
+		return purpose, warnings, nil
+	}
+	if !purposeLenient.Load() {
+		return "", nil, fieldErrorf("purpose", CodeUnknownPurposeCode, "unknown purpose code")
+	}
+	return purpose, append(warnings, Warning{
+		Field:   "purpose",
+		Code:    CodeUnknownPurposeCode,
+		Message: fmt.Sprintf("purpose code %q is not in the ISO 20022 ExternalPurpose1Code list", purpose),
+	}), nil
+}
+
+// parseSchemeAmount parses the amount of a non-EPC scheme, honouring
+// open_amount. max is the largest amount in cents the scheme can carry.
+func parseSchemeAmount(in Input, currency string, max int64) (int64, error) {
+	if in.OpenAmount {
+		if strings.TrimSpace(in.Amount) != "" {
+			return 0, fieldErrorf("open_amount", CodeConflict, "amount and open_amount are mutually exclusive")
+		}
+		return 0, nil
+	}
+	cents, err := parseAmount(in.Amount, in.AmountFormat, currency)
+	if err != nil {
+		return 0, err
+	}
+	if cents <= 0 {
+		return 0, fieldErrorf("amount", CodeInvalid, "amount must be > 0")
+	}
+	if cents > max {
+		return 0, fieldErrorf("amount", CodeTooLarge, "amount too large")
+	}
+	return cents, nil
+}
+
+// oneLine replaces tabs and line breaks with spaces for schemes whose
+// payload uses them as field separators.
+var oneLine = strings.NewReplacer("\t", " ", "\r\n", " ", "\r", " ", "\n", " ")
+
+// transliterateAllowed replaces every rune outside a scheme's character set
+// with its Latin fallback, or "?" when the fallback is not allowed either.
+func transliterateAllowed(field, v string, allowed func(rune) bool, acc []Transliteration) (string, []Transliteration) {
+	return transliterateWith(field, v, allowed, charset.Fallback, acc)
+}
+
+// transliterateWith is transliterateAllowed with a custom fallback table.
+func transliterateWith(field, v string, allowed func(rune) bool, fallback func(rune) string, acc []Transliteration) (string, []Transliteration) {
+	var b strings.Builder
+	seen := map[rune]bool{}
+	for _, r := range v {
+		if allowed(r) {
+			b.WriteRune(r)
+			continue
+		}
+		to := fallback(r)
+		for _, fr := range to {
+			if !allowed(fr) {
+				to = "?"
+				break
+			}
+		}
+		b.WriteString(to)
+		if !seen[r] {
+			seen[r] = true
+			acc = append(acc, Transliteration{Field: field, From: string(r), To: to})
+		}
+	}
+	return b.String(), acc
+}
+
+// ValidBIC reports whether bic has the shape of an 8 or 11 character BIC.
+func ValidBIC(bic string) bool {
+	return reBIC.MatchString(bic)
 }
 
 func truncateRunes(s string, max int) string {
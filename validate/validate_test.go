@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -43,6 +44,47 @@ func TestCleanAndValidate_Table(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "version_002_without_bic_in_eea",
+			in: Input{
+				Version: "002",
+				Name:    "Example GmbH",
+				IBAN:    "DE12500105170648489890",
+				Amount:  "49.90",
+			},
+			wantErr: false,
+		},
+		{
+			name: "version_002_without_bic_outside_eea",
+			in: Input{
+				Version: "002",
+				Name:    "Example Ltd",
+				IBAN:    "GB82WEST12345698765432",
+				Amount:  "49.90",
+			},
+			wantErr: true,
+		},
+		{
+			name: "version_001_without_bic",
+			in: Input{
+				Version: "001",
+				Name:    "Example GmbH",
+				IBAN:    "DE12500105170648489890",
+				Amount:  "49.90",
+			},
+			wantErr: true,
+		},
+		{
+			name: "version_unsupported",
+			in: Input{
+				Version: "003",
+				Name:    "Example GmbH",
+				IBAN:    "DE12500105170648489890",
+				BIC:     "INGDDEFFXXX",
+				Amount:  "49.90",
+			},
+			wantErr: true,
+		},
 		{
 			name: "valid_basic",
 			in: Input{
@@ -94,16 +136,38 @@ func TestCleanAndValidate_Table(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "purpose_too_long_truncates",
+			name: "purpose_known_lowercase",
 			in: Input{
 				Name:    "Example GmbH",
 				IBAN:    "DE12500105170648489890",
 				BIC:     "INGDDEFFXXX",
 				Amount:  "1",
-				Purpose: "ABCDE",
+				Purpose: "sala",
 			},
 			wantErr: false,
 		},
+		{
+			name: "purpose_unknown",
+			in: Input{
+				Name:    "Example GmbH",
+				IBAN:    "DE12500105170648489890",
+				BIC:     "INGDDEFFXXX",
+				Amount:  "1",
+				Purpose: "XXXX",
+			},
+			wantErr: true,
+		},
+		{
+			name: "purpose_too_long",
+			in: Input{
+				Name:    "Example GmbH",
+				IBAN:    "DE12500105170648489890",
+				BIC:     "INGDDEFFXXX",
+				Amount:  "1",
+				Purpose: "GDDSX",
+			},
+			wantErr: true,
+		},
 		{
 			name: "amount_invalid_format",
 			in: Input{
@@ -167,6 +231,47 @@ func TestCleanAndValidate_Table(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "open_amount",
+			in: Input{
+				Name:       "Example e.V.",
+				IBAN:       "DE12500105170648489890",
+				BIC:        "INGDDEFFXXX",
+				OpenAmount: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "open_amount_with_amount",
+			in: Input{
+				Name:       "Example e.V.",
+				IBAN:       "DE12500105170648489890",
+				BIC:        "INGDDEFFXXX",
+				Amount:     "10",
+				OpenAmount: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "amount_missing_without_open_amount",
+			in: Input{
+				Name: "Example e.V.",
+				IBAN: "DE12500105170648489890",
+				BIC:  "INGDDEFFXXX",
+			},
+			wantErr: true,
+		},
+		{
+			name: "currency_not_eur",
+			in: Input{
+				Name:     "Example GmbH",
+				IBAN:     "DE12500105170648489890",
+				BIC:      "INGDDEFFXXX",
+				Amount:   "1",
+				Currency: "CHF",
+			},
+			wantErr: true,
+		},
 		{
 			name: "mutual_exclusion",
 			in: Input{
@@ -198,7 +303,7 @@ func TestCleanAndValidate_TruncationAndDefaults(t *testing.T) {
 		IBAN:        "DE12500105170648489890",
 		BIC:         "INGDDEFFXXX",
 		Amount:      "1",
-		Purpose:     "abcdE",
+		Purpose:     "suPP",
 		Information: long,
 	}
 	cleaned, err := CleanAndValidate(in)
@@ -211,8 +316,11 @@ func TestCleanAndValidate_TruncationAndDefaults(t *testing.T) {
 	if cleaned.Scheme != "epc_sct" {
 		t.Fatalf("expected default scheme epc_sct, got %q", cleaned.Scheme)
 	}
-	if cleaned.Purpose != "ABCD" {
-		t.Fatalf("expected purpose uppercased+truncated to ABCD, got %q", cleaned.Purpose)
+	if cleaned.Version != "001" {
+		t.Fatalf("expected default version 001, got %q", cleaned.Version)
+	}
+	if cleaned.Purpose != "SUPP" {
+		t.Fatalf("expected purpose uppercased to SUPP, got %q", cleaned.Purpose)
 	}
 	if len(cleaned.Information) != 70 {
 		t.Fatalf("expected information truncated to 70, got %d", len(cleaned.Information))
@@ -226,8 +334,22 @@ func TestCleanAndValidate_UnicodeTruncationByRunes(t *testing.T) {
 	name := strings.Repeat("Ж", 80)
 	info := strings.Repeat("あ", 90)
 
+	// Both fields together exceed the 331-byte payload limit, so check them separately.
 	cleaned, err := CleanAndValidate(Input{
-		Name:        name,
+		Name:   name,
+		IBAN:   "DE12500105170648489890",
+		BIC:    "INGDDEFFXXX",
+		Amount: "1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len([]rune(cleaned.Name)); got != 70 {
+		t.Fatalf("expected name truncated to 70 runes, got %d", got)
+	}
+
+	cleaned, err = CleanAndValidate(Input{
+		Name:        "Example GmbH",
 		IBAN:        "DE12500105170648489890",
 		BIC:         "INGDDEFFXXX",
 		Amount:      "1",
@@ -236,11 +358,109 @@ func TestCleanAndValidate_UnicodeTruncationByRunes(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	if got := len([]rune(cleaned.Name)); got != 70 {
-		t.Fatalf("expected name truncated to 70 runes, got %d", got)
-	}
 	if got := len([]rune(cleaned.Information)); got != 70 {
 		t.Fatalf("expected information truncated to 70 runes, got %d", got)
 	}
 }
+
+func TestCleanAndValidate_PayloadByteLimit(t *testing.T) {
+	in := Input{
+		Name:        strings.Repeat("Ж", 80),
+		IBAN:        "DE12500105170648489890",
+		BIC:         "INGDDEFFXXX",
+		Amount:      "1",
+		Information: strings.Repeat("あ", 90),
+	}
+	_, err := CleanAndValidate(in)
+	var tooLarge *PayloadTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("expected PayloadTooLargeError, got %v", err)
+	}
+	if tooLarge.Usage.Total <= EPCMaxPayloadBytes || tooLarge.Usage.Remaining >= 0 {
+		t.Fatalf("unexpected usage: %+v", tooLarge.Usage)
+	}
+
+	// The same text fits once transliterated into a single-byte charset.
+	in.Charset = "iso-8859-5"
+	in.Information = strings.Repeat("Ж", 90)
+	cleaned, err := CleanAndValidate(in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var nameBytes int
+	sum := cleaned.ByteUsage.Overhead
+	for _, f := range cleaned.ByteUsage.Fields {
+		sum += f.Bytes
+		if f.Field == "name" {
+			nameBytes = f.Bytes
+		}
+	}
+	if nameBytes != 70 {
+		t.Fatalf("expected 70 name bytes in ISO-8859-5, got %d", nameBytes)
+	}
+	if sum != cleaned.ByteUsage.Total || cleaned.ByteUsage.Total > EPCMaxPayloadBytes {
+		t.Fatalf("inconsistent usage: %+v", cleaned.ByteUsage)
+	}
+}
+
+func TestCleanAndValidate_OpenAmountByteUsage(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Name:       "Example e.V.",
+		IBAN:       "DE12500105170648489890",
+		BIC:        "INGDDEFFXXX",
+		OpenAmount: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cleaned.OpenAmount || cleaned.AmountCents != 0 {
+		t.Fatalf("expected open amount, got %+v", cleaned)
+	}
+	for _, f := range cleaned.ByteUsage.Fields {
+		if f.Field == "amount" && f.Bytes != 0 {
+			t.Fatalf("expected empty amount field, got %+v", f)
+		}
+	}
+}
+
+func TestCleanAndValidate_CharsetTransliteration(t *testing.T) {
+	cleaned, err := CleanAndValidate(Input{
+		Charset:        "iso-8859-1",
+		Name:           "Сервис Москва",
+		IBAN:           "DE12500105170648489890",
+		BIC:            "INGDDEFFXXX",
+		Amount:         "1",
+		RemittanceText: "Müller",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleaned.Charset != 2 {
+		t.Fatalf("expected charset 2, got %d", cleaned.Charset)
+	}
+	if cleaned.Name != "Servis Moskva" {
+		t.Fatalf("unexpected transliterated name: %q", cleaned.Name)
+	}
+	if cleaned.RemittanceText != "Müller" {
+		t.Fatalf("latin-1 text should be kept: %q", cleaned.RemittanceText)
+	}
+	for _, tr := range cleaned.Transliterations {
+		if tr.Field != "name" {
+			t.Fatalf("unexpected transliteration field: %+v", tr)
+		}
+	}
+	if len(cleaned.Transliterations) == 0 {
+		t.Fatalf("expected transliterations to be reported")
+	}
+
+	_, err = CleanAndValidate(Input{
+		Charset: "iso-8859-3",
+		Name:    "Example GmbH",
+		IBAN:    "DE12500105170648489890",
+		BIC:     "INGDDEFFXXX",
+		Amount:  "1",
+	})
+	if err == nil || err.Error() != "unsupported charset" {
+		t.Fatalf("expected unsupported charset, got %v", err)
+	}
+}